@@ -0,0 +1,25 @@
+import { Suspense } from "react"
+
+import { SocketProvider } from "../contexts/SocketContext"
+import { MessageQueueProvider } from "../contexts/MessageQueueContext"
+import Clipboard from "./Clipboard"
+import Loading from "./Loading"
+
+interface Props {
+    clipId: string
+    token: string
+    viewerToken: string
+    password: string // empty if the clip has none
+}
+
+export default function ClipView({ clipId, token, viewerToken, password }: Props) {
+    return (
+        <Suspense fallback={<Loading />}>
+            <MessageQueueProvider>
+                <SocketProvider clipId={clipId} token={token} password={password}>
+                    <Clipboard clipId={clipId} token={token} viewerToken={viewerToken} />
+                </SocketProvider>
+            </MessageQueueProvider>
+        </Suspense>
+    )
+}
@@ -1,6 +1,6 @@
 "use client"
 
-import { useContext, useEffect, useRef, useState } from "react"
+import React, { useContext, useEffect, useRef, useState } from "react"
 import { FaRegTrashCan, FaRegCopy } from "react-icons/fa6"
 import { ClipLoader } from "react-spinners"
 import ClipboardJS from "clipboard"
@@ -17,9 +17,11 @@ import styles from "./Clipboard.module.css"
 
 interface Props {
     clipId: string
+    token: string
+    viewerToken: string // only known to the creator of the clip
 }
 
-export default function Clipboard({ clipId }: Props) {
+export default function Clipboard({ clipId, token, viewerToken }: Props) {
     const { pushMessage } = useContext(MessageQueueContext)
     const bodyRef = useContext(BodyRefContext)
 
@@ -43,6 +45,13 @@ export default function Clipboard({ clipId }: Props) {
         pushMessage({ type: MessageType.INFO, text: "Copied" })
     }
 
+    const copyLink = (e: React.MouseEvent<HTMLAnchorElement>) => {
+        e.preventDefault()
+
+        ClipboardJS.copy(e.currentTarget.href)
+        pushMessage({ type: MessageType.INFO, text: "Link Copied" })
+    }
+
     const paste = (e: ClipboardEvent) => {
         const items = e.clipboardData?.items
         if (!items || items.length !== 1) { return }
@@ -95,6 +104,17 @@ export default function Clipboard({ clipId }: Props) {
                         <strong>{clipId}</strong>
                     </div>
 
+                    <div className={styles.links}>
+                        <a href={`/${clipId}?token=${encodeURIComponent(token)}`} title={`${clipId}:${token}`} onClick={copyLink}>
+                            Link
+                        </a>
+                        {viewerToken &&
+                            <a href={`/${clipId}?token=${encodeURIComponent(viewerToken)}`} title={`${clipId}:${viewerToken}`} onClick={copyLink}>
+                                Viewer Link
+                            </a>
+                        }
+                    </div>
+
                     <textarea
                         ref={inputRef}
                         value={renderedContents}
@@ -0,0 +1,62 @@
+"use client"
+
+import React, { useState, useTransition } from "react"
+import { ClipLoader } from "react-spinners"
+
+import { ClipStatus, checkClip } from "../../actions"
+import ControlButton from "@/components/ControlButton"
+import ClipView from "./ClipView"
+import styles from "./PasswordPrompt.module.css"
+
+interface Props {
+    clipId: string
+    token: string
+    viewerToken: string
+    status: ClipStatus // why the clip could not be opened without a password
+}
+
+export default function PasswordPrompt({ clipId, token, viewerToken, status: initial }: Props) {
+    const [password, setPassword] = useState("")
+    const [status, setStatus] = useState(initial)
+
+    const [isPending, startTransition] = useTransition()
+
+    // the password is only kept in memory, and sent along with the websocket
+    if (status === "ok") {
+        return <ClipView clipId={clipId} token={token} viewerToken={viewerToken} password={password} />
+    }
+
+    const submit = (e: React.FormEvent) => {
+        e.preventDefault()
+
+        startTransition(async () => {
+            setStatus(await checkClip(clipId, token, password))
+        })
+    }
+
+    return (
+        <form className={styles.container} onSubmit={submit}>
+            <strong>{clipId}</strong>
+
+            <input
+                type="password"
+                value={password}
+                placeholder="password"
+                className={status === "wrong-password" ? styles.wrong : ""}
+                onChange={e => { setPassword(e.target.value); setStatus("password-required") }}
+                autoFocus
+            />
+
+            <ControlButton type="submit" disabled={isPending || password === ""}>Open</ControlButton>
+
+            <div className={styles.status}>
+                {status === "wrong-password" && "Wrong password"}
+                {status === "rate-limited" && "Too many attempts, try again later"}
+                {status === "invalid-token" && "Invalid token"}
+                {status === "not-found" && "No such clip"}
+            </div>
+
+            {isPending && <ClipLoader />}
+        </form>
+    )
+}
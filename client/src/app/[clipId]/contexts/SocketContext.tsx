@@ -22,9 +22,11 @@ interface WS {
 
 interface Props {
     clipId: string
+    token: string
+    password: string // empty if the clip has none
 }
 
-export function SocketProvider({ clipId, children }: React.PropsWithChildren<Props>) {
+export function SocketProvider({ clipId, token, password, children }: React.PropsWithChildren<Props>) {
     const { pushMessage } = useContext(MessageQueueContext)
 
     const socketRef = useRef<WS>({ ws: null, ok: false })
@@ -41,7 +43,12 @@ export function SocketProvider({ clipId, children }: React.PropsWithChildren<Pro
         if (!reconnect) { return }
         setReconnect(false)
 
-        const ws = new WebSocket(`/ws/${clipId}`)
+        let url = `/ws/${clipId}?token=${encodeURIComponent(token)}`
+        if (password) {
+            url += `&password=${encodeURIComponent(password)}`
+        }
+
+        const ws = new WebSocket(url)
         ws.binaryType = "arraybuffer"
 
         socketRef.current.ws = ws
@@ -59,6 +66,9 @@ export function SocketProvider({ clipId, children }: React.PropsWithChildren<Pro
         ws.onopen = () => {
             clearTimeout(connDeadline)
 
+            // saying hello spares waiting for the server to take us for a legacy client
+            ws.send(Message.encode(Message.create({ hello: { version: 1 } })).finish())
+
             socketRef.current.ok = true
             setSocketOk(true)
             pushMessage({ type: MessageType.SUCCESS, text: "Server Connected" })
@@ -5,7 +5,7 @@ import { useState, useEffect, useContext, useRef } from "react"
 import SocketContext from "./contexts/SocketContext"
 import MessageQueueContext, { MessageType } from "./contexts/MessageQueueContext"
 import type { Contents } from "./types/clipboard"
-import { type FileHeader, Message, type Chunk } from "@/pb/clip"
+import { FileHeader, Message, Chunk } from "@/pb/clip"
 
 interface Disconnected {
     type: "Disconnected"
@@ -87,10 +87,10 @@ const cut = async (data: Blob) => {
 
     const chunks = []
     for (let i = 0; i < numChunks; i++) {
-        chunks.push({
+        chunks.push(Chunk.create({
             index: i,
             data: bytes.slice(i * chunkSize, i * chunkSize + chunkSize)
-        })
+        }))
     }
 
     return chunks
@@ -233,6 +233,8 @@ export function useSocketContents() {
                 setSocketState({ type: "Idle" })
                 pushMessage({ type: MessageType.ERROR, text: err.desc })
             }
+        } else if (m.welcome) {
+            return
         } else {
             setSocketState({ type: "Idle" })
             pushMessage({ type: MessageType.ERROR, text: "Unexpected message" })
@@ -275,7 +277,7 @@ export function useSocketContents() {
 
                 case "file":
                     const chunks = await chunksPromise
-                    const header = { filename: contents.filename, contentType: contents.contentType, numChunks: chunks.length }
+                    const header = FileHeader.create({ filename: contents.filename, contentType: contents.contentType, numChunks: chunks.length })
 
                     setSocketState({
                         type: "SendingFile",
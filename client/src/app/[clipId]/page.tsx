@@ -1,29 +1,25 @@
-import { Suspense } from "react"
 import { notFound } from "next/navigation"
 
 import { checkClip } from "../actions"
-import { SocketProvider } from "./contexts/SocketContext"
-import { MessageQueueProvider } from "./contexts/MessageQueueContext"
-import Clipboard from "./components/Clipboard"
-import Loading from "./components/Loading"
+import ClipView from "./components/ClipView"
+import PasswordPrompt from "./components/PasswordPrompt"
 
 interface Props {
     params: Promise<{ clipId: string }>
+    searchParams: Promise<{ token?: string, viewer?: string }>
 }
 
-export default async function Page({ params }: Props) {
+export default async function Page({ params, searchParams }: Props) {
     const clipId = (await params).clipId
+    const { token = "", viewer = "" } = await searchParams
 
-    const ok = await checkClip(clipId)
-    if (!ok) { notFound() }
+    const status = await checkClip(clipId, token)
+    if (status === "not-found") { notFound() }
+    if (status === "invalid-token") { throw new Error("Invalid Token") }
 
-    return (
-        <Suspense fallback={<Loading />}>
-            <MessageQueueProvider>
-                <SocketProvider clipId={clipId}>
-                    <Clipboard clipId={clipId} />
-                </SocketProvider>
-            </MessageQueueProvider>
-        </Suspense>
-    )
+    if (status === "password-required" || status === "rate-limited") {
+        return <PasswordPrompt clipId={clipId} token={token} viewerToken={viewer} status={status} />
+    }
+
+    return <ClipView clipId={clipId} token={token} viewerToken={viewer} password="" />
 }
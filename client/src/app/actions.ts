@@ -1,39 +1,84 @@
 "use server"
 
+import { headers } from "next/headers"
 import { redirect } from "next/navigation"
 
+// ClipStatus tells whether a clip may be opened with a token and a password
+export type ClipStatus = "ok" | "not-found" | "invalid-token" | "password-required" | "wrong-password" | "rate-limited"
+
 export async function newclip() {
     const resp = await fetch(`http://${process.env.SERVER}:5000/newclip`, { cache: "no-store" })
     if (!resp.ok) {
         throw new Error(await resp.text())
     }
 
-    return await resp.text()
+    return {
+        id: await resp.text(),
+        token: resp.headers.get("X-Editor-Token") ?? "",
+        viewerToken: resp.headers.get("X-Viewer-Token") ?? "",
+    }
 }
 
-export async function checkClip(id: string) {
-    const resp = await fetch(`http://${process.env.SERVER}:5000/check/${id}`, { cache: "no-store" })
+// forwarded returns the headers of a request made to the server on behalf of the client, which the server
+// throttles failed password attempts of by address
+async function forwarded(password: string) {
+    const h: Record<string, string> = {}
+
+    const addr = (await headers()).get("x-forwarded-for")
+    if (addr) {
+        h["X-Forwarded-For"] = addr
+    }
+
+    if (password) {
+        h["Authorization"] = `Basic ${Buffer.from(`:${password}`).toString("base64")}`
+    }
+
+    return h
+}
+
+export async function checkClip(id: string, token: string, password = ""): Promise<ClipStatus> {
+    const resp = await fetch(`http://${process.env.SERVER}:5000/check/${id}?token=${encodeURIComponent(token)}`, {
+        cache: "no-store",
+        headers: await forwarded(password),
+    })
 
     if (resp.ok) {
-        return true
+        return "ok"
+    }
+
+    if (resp.status === 401) {
+        return password ? "wrong-password" : "password-required"
+    }
+
+    if (resp.status === 429) {
+        return "rate-limited"
     }
 
     if (resp.status === 404) {
-        return false
+        return "not-found"
+    }
+
+    if (resp.status === 403) {
+        return "invalid-token"
     }
 
     throw new Error(await resp.text())
 }
 
-export async function clipRedirect(id: string | null) {
-    if (id) {
-        if (!await checkClip(id)) {
-            return
+// clipRedirect goes to the clip named by an identifier of the form ID:TOKEN, which asks for the password
+// if the clip has one, or to a new clip, whose viewer token is passed along so that the page can link to it.
+// It returns why the clip cannot be opened otherwise.
+export async function clipRedirect(identifier: string | null): Promise<ClipStatus> {
+    if (identifier) {
+        const [id, token = ""] = identifier.split(":", 2)
+        const status = await checkClip(id, token)
+        if (status !== "ok" && status !== "password-required") {
+            return status
         }
 
-        redirect(`/${id}`)
+        redirect(`/${id}?token=${encodeURIComponent(token)}`)
     } else {
-        const id = await newclip()
-        redirect(`/${id}`)
+        const { id, token, viewerToken } = await newclip()
+        redirect(`/${id}?token=${encodeURIComponent(token)}&viewer=${encodeURIComponent(viewerToken)}`)
     }
 }
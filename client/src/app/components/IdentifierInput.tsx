@@ -1,13 +1,16 @@
 "use client"
 
-import React, { useState, useEffect } from "react"
+import React, { useState, useEffect, useRef } from "react"
 
-import { clipRedirect } from "../actions"
+import { ClipStatus, clipRedirect } from "../actions"
 import InputBox from "./InputBox"
 import styles from "./IdentifierInput.module.css"
 
 const COUNT = 6
 
+// ID matches the id of pasted identifiers of the form ID:TOKEN
+const ID = /^([a-z0-9]{2})-?([a-z0-9]{2})-?([a-z0-9]{2})$/
+
 interface Props {
     startTransition: React.TransitionStartFunction
 }
@@ -16,6 +19,9 @@ export default function IndetifierInput({ startTransition }: Props) {
     const [input, setInput] = useState("")
     const cursor = input.length
 
+    const [token, setToken] = useState("")
+    const tokenRef = useRef<HTMLInputElement>(null)
+
     const next = (c: string) => {
         cursor < COUNT && setInput(input + c)
     }
@@ -24,33 +30,61 @@ export default function IndetifierInput({ startTransition }: Props) {
         cursor > 0 && setInput(input.slice(0, cursor - 1))
     }
 
-    const [notFound, setNotFound] = useState(false)
-
-    useEffect(() => {
-        setNotFound(false)
-
-        if (cursor !== COUNT) { return }
+    const [status, setStatus] = useState<ClipStatus>("ok")
 
+    const open = (input: string, token: string) => {
         const id = input.slice(0, 2) + "-" + input.slice(2, 4) + "-" + input.slice(4, 6)
         startTransition(async () => {
-            await clipRedirect(id)
-            setNotFound(true)
+            setStatus(await clipRedirect(`${id}:${token}`))
         })
+    }
+
+    useEffect(() => {
+        setStatus("ok")
+
+        // the token follows the id
+        cursor === COUNT && tokenRef.current?.focus()
     }, [input])
 
+    const paste = (e: React.ClipboardEvent) => {
+        const [id, token = ""] = e.clipboardData.getData("text").trim().split(":", 2)
+        const match = ID.exec(id.toLowerCase())
+        if (!match) { return }
+        e.preventDefault()
+
+        const input = match[1] + match[2] + match[3]
+        setInput(input)
+        setToken(token)
+        open(input, token)
+    }
+
+    const tokenKey = (e: React.KeyboardEvent) => {
+        switch (e.key) {
+            case "Enter":
+                e.stopPropagation()
+                open(input, token)
+                break
+
+            case "Backspace":
+                token === "" && prev()
+                break
+
+            default:
+        }
+    }
+
     return (
-        <div className={styles.container}>
+        <div className={styles.container} onPaste={paste}>
             <div className={styles.row}>
                 {[0, 1].map(index => (
                     <InputBox
                         key={index}
                         index={index}
-                        count={COUNT}
                         cursor={cursor}
                         input={input}
                         next={next}
                         prev={prev}
-                        notFound={notFound}
+                        notFound={status === "not-found"}
                     />
                 ))}
                 <h1>-</h1>
@@ -58,12 +92,11 @@ export default function IndetifierInput({ startTransition }: Props) {
                     <InputBox
                         key={index}
                         index={index}
-                        count={COUNT}
                         cursor={cursor}
                         input={input}
                         next={next}
                         prev={prev}
-                        notFound={notFound}
+                        notFound={status === "not-found"}
                     />
                 ))}
                 <h1>-</h1>
@@ -71,15 +104,30 @@ export default function IndetifierInput({ startTransition }: Props) {
                     <InputBox
                         key={index}
                         index={index}
-                        count={COUNT}
                         cursor={cursor}
                         input={input}
                         next={next}
                         prev={prev}
-                        notFound={notFound}
+                        notFound={status === "not-found"}
                     />
                 ))}
             </div>
+
+            <input
+                ref={tokenRef}
+                value={token}
+                placeholder="token"
+                className={`${styles.token} ${status === "invalid-token" ? styles.invalid : ""}`}
+                disabled={cursor !== COUNT}
+                onChange={e => { setToken(e.target.value.trim()); setStatus("ok") }}
+                onKeyDown={tokenKey}
+            />
+
+            <div className={styles.status}>
+                {status === "not-found" && "No such clip"}
+                {status === "invalid-token" && "Invalid token"}
+                {status === "rate-limited" && "Too many attempts, try again later"}
+            </div>
         </div>
     )
 }
@@ -6,7 +6,6 @@ import styles from "./InputBox.module.css"
 
 interface Props {
     index: number
-    count: number
     cursor: number
     input: string
     next: (c: string) => void
@@ -14,10 +13,9 @@ interface Props {
     notFound: boolean
 }
 
-export default function InputBox({ index, count, cursor, input, next, prev, notFound }: Props) {
-    const isLast = index === count - 1
-    const lastActive = cursor === count
-    const isActive = index === cursor || (isLast && lastActive)
+export default function InputBox({ index, cursor, input, next, prev, notFound }: Props) {
+    // once the id is complete, the token input has the focus
+    const isActive = index === cursor
 
     const ref = useRef<HTMLInputElement>(null)
 
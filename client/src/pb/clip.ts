@@ -9,6 +9,312 @@ import { BinaryReader, BinaryWriter } from "@bufbuild/protobuf/wire";
 
 export const protobufPackage = "clip";
 
+export enum Cipher {
+  CIPHER_UNSPECIFIED = 0,
+  CIPHER_AES_256_GCM = 1,
+  CIPHER_XCHACHA20_POLY1305 = 2,
+  UNRECOGNIZED = -1,
+}
+
+export function cipherFromJSON(object: any): Cipher {
+  switch (object) {
+    case 0:
+    case "CIPHER_UNSPECIFIED":
+      return Cipher.CIPHER_UNSPECIFIED;
+    case 1:
+    case "CIPHER_AES_256_GCM":
+      return Cipher.CIPHER_AES_256_GCM;
+    case 2:
+    case "CIPHER_XCHACHA20_POLY1305":
+      return Cipher.CIPHER_XCHACHA20_POLY1305;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return Cipher.UNRECOGNIZED;
+  }
+}
+
+export function cipherToJSON(object: Cipher): string {
+  switch (object) {
+    case Cipher.CIPHER_UNSPECIFIED:
+      return "CIPHER_UNSPECIFIED";
+    case Cipher.CIPHER_AES_256_GCM:
+      return "CIPHER_AES_256_GCM";
+    case Cipher.CIPHER_XCHACHA20_POLY1305:
+      return "CIPHER_XCHACHA20_POLY1305";
+    case Cipher.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
+}
+
+export enum KdfAlgorithm {
+  KDF_ALGORITHM_NONE = 0,
+  KDF_ALGORITHM_HKDF_SHA256 = 1,
+  KDF_ALGORITHM_PBKDF2_SHA256 = 2,
+  UNRECOGNIZED = -1,
+}
+
+export function kdfAlgorithmFromJSON(object: any): KdfAlgorithm {
+  switch (object) {
+    case 0:
+    case "KDF_ALGORITHM_NONE":
+      return KdfAlgorithm.KDF_ALGORITHM_NONE;
+    case 1:
+    case "KDF_ALGORITHM_HKDF_SHA256":
+      return KdfAlgorithm.KDF_ALGORITHM_HKDF_SHA256;
+    case 2:
+    case "KDF_ALGORITHM_PBKDF2_SHA256":
+      return KdfAlgorithm.KDF_ALGORITHM_PBKDF2_SHA256;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return KdfAlgorithm.UNRECOGNIZED;
+  }
+}
+
+export function kdfAlgorithmToJSON(object: KdfAlgorithm): string {
+  switch (object) {
+    case KdfAlgorithm.KDF_ALGORITHM_NONE:
+      return "KDF_ALGORITHM_NONE";
+    case KdfAlgorithm.KDF_ALGORITHM_HKDF_SHA256:
+      return "KDF_ALGORITHM_HKDF_SHA256";
+    case KdfAlgorithm.KDF_ALGORITHM_PBKDF2_SHA256:
+      return "KDF_ALGORITHM_PBKDF2_SHA256";
+    case KdfAlgorithm.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
+}
+
+export enum Capability {
+  CAPABILITY_UNSPECIFIED = 0,
+  CAPABILITY_WINDOWING = 1,
+  CAPABILITY_CHECKSUMS = 2,
+  CAPABILITY_RESUME = 3,
+  CAPABILITY_COMPRESSION = 4,
+  CAPABILITY_ITEMS = 5,
+  CAPABILITY_ENCRYPTION = 6,
+  CAPABILITY_EXPIRY = 7,
+  UNRECOGNIZED = -1,
+}
+
+export function capabilityFromJSON(object: any): Capability {
+  switch (object) {
+    case 0:
+    case "CAPABILITY_UNSPECIFIED":
+      return Capability.CAPABILITY_UNSPECIFIED;
+    case 1:
+    case "CAPABILITY_WINDOWING":
+      return Capability.CAPABILITY_WINDOWING;
+    case 2:
+    case "CAPABILITY_CHECKSUMS":
+      return Capability.CAPABILITY_CHECKSUMS;
+    case 3:
+    case "CAPABILITY_RESUME":
+      return Capability.CAPABILITY_RESUME;
+    case 4:
+    case "CAPABILITY_COMPRESSION":
+      return Capability.CAPABILITY_COMPRESSION;
+    case 5:
+    case "CAPABILITY_ITEMS":
+      return Capability.CAPABILITY_ITEMS;
+    case 6:
+    case "CAPABILITY_ENCRYPTION":
+      return Capability.CAPABILITY_ENCRYPTION;
+    case 7:
+    case "CAPABILITY_EXPIRY":
+      return Capability.CAPABILITY_EXPIRY;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return Capability.UNRECOGNIZED;
+  }
+}
+
+export function capabilityToJSON(object: Capability): string {
+  switch (object) {
+    case Capability.CAPABILITY_UNSPECIFIED:
+      return "CAPABILITY_UNSPECIFIED";
+    case Capability.CAPABILITY_WINDOWING:
+      return "CAPABILITY_WINDOWING";
+    case Capability.CAPABILITY_CHECKSUMS:
+      return "CAPABILITY_CHECKSUMS";
+    case Capability.CAPABILITY_RESUME:
+      return "CAPABILITY_RESUME";
+    case Capability.CAPABILITY_COMPRESSION:
+      return "CAPABILITY_COMPRESSION";
+    case Capability.CAPABILITY_ITEMS:
+      return "CAPABILITY_ITEMS";
+    case Capability.CAPABILITY_ENCRYPTION:
+      return "CAPABILITY_ENCRYPTION";
+    case Capability.CAPABILITY_EXPIRY:
+      return "CAPABILITY_EXPIRY";
+    case Capability.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
+}
+
+export enum ErrorCode {
+  ERROR_CODE_UNSPECIFIED = 0,
+  ERROR_CODE_INTERNAL = 1,
+  ERROR_CODE_UNEXPECTED_MESSAGE = 2,
+  ERROR_CODE_INVALID_CLIP = 3,
+  ERROR_CODE_BUSY = 4,
+  ERROR_CODE_DISORDERED = 5,
+  ERROR_CODE_TOO_LARGE = 6,
+  ERROR_CODE_UNAUTHORIZED = 7,
+  ERROR_CODE_RATE_LIMITED = 8,
+  ERROR_CODE_PROTOCOL_MISMATCH = 9,
+  ERROR_CODE_CHECKSUM_MISMATCH = 10,
+  ERROR_CODE_INVALID_RANGE = 11,
+  ERROR_CODE_FILE_CHANGED = 12,
+  ERROR_CODE_UPLOAD_ABORTED = 13,
+  ERROR_CODE_INVALID_CHUNK = 14,
+  ERROR_CODE_INVALID_VERSION = 15,
+  ERROR_CODE_INVALID_ITEM = 16,
+  ERROR_CODE_INVALID_MANIFEST = 17,
+  ERROR_CODE_INVALID_PATH = 18,
+  ERROR_CODE_INVALID_ENVELOPE = 19,
+  ERROR_CODE_ENCRYPTION_REQUIRED = 20,
+  ERROR_CODE_BURN_AFTER_READING = 21,
+  ERROR_CODE_INVALID_LIFETIME = 22,
+  UNRECOGNIZED = -1,
+}
+
+export function errorCodeFromJSON(object: any): ErrorCode {
+  switch (object) {
+    case 0:
+    case "ERROR_CODE_UNSPECIFIED":
+      return ErrorCode.ERROR_CODE_UNSPECIFIED;
+    case 1:
+    case "ERROR_CODE_INTERNAL":
+      return ErrorCode.ERROR_CODE_INTERNAL;
+    case 2:
+    case "ERROR_CODE_UNEXPECTED_MESSAGE":
+      return ErrorCode.ERROR_CODE_UNEXPECTED_MESSAGE;
+    case 3:
+    case "ERROR_CODE_INVALID_CLIP":
+      return ErrorCode.ERROR_CODE_INVALID_CLIP;
+    case 4:
+    case "ERROR_CODE_BUSY":
+      return ErrorCode.ERROR_CODE_BUSY;
+    case 5:
+    case "ERROR_CODE_DISORDERED":
+      return ErrorCode.ERROR_CODE_DISORDERED;
+    case 6:
+    case "ERROR_CODE_TOO_LARGE":
+      return ErrorCode.ERROR_CODE_TOO_LARGE;
+    case 7:
+    case "ERROR_CODE_UNAUTHORIZED":
+      return ErrorCode.ERROR_CODE_UNAUTHORIZED;
+    case 8:
+    case "ERROR_CODE_RATE_LIMITED":
+      return ErrorCode.ERROR_CODE_RATE_LIMITED;
+    case 9:
+    case "ERROR_CODE_PROTOCOL_MISMATCH":
+      return ErrorCode.ERROR_CODE_PROTOCOL_MISMATCH;
+    case 10:
+    case "ERROR_CODE_CHECKSUM_MISMATCH":
+      return ErrorCode.ERROR_CODE_CHECKSUM_MISMATCH;
+    case 11:
+    case "ERROR_CODE_INVALID_RANGE":
+      return ErrorCode.ERROR_CODE_INVALID_RANGE;
+    case 12:
+    case "ERROR_CODE_FILE_CHANGED":
+      return ErrorCode.ERROR_CODE_FILE_CHANGED;
+    case 13:
+    case "ERROR_CODE_UPLOAD_ABORTED":
+      return ErrorCode.ERROR_CODE_UPLOAD_ABORTED;
+    case 14:
+    case "ERROR_CODE_INVALID_CHUNK":
+      return ErrorCode.ERROR_CODE_INVALID_CHUNK;
+    case 15:
+    case "ERROR_CODE_INVALID_VERSION":
+      return ErrorCode.ERROR_CODE_INVALID_VERSION;
+    case 16:
+    case "ERROR_CODE_INVALID_ITEM":
+      return ErrorCode.ERROR_CODE_INVALID_ITEM;
+    case 17:
+    case "ERROR_CODE_INVALID_MANIFEST":
+      return ErrorCode.ERROR_CODE_INVALID_MANIFEST;
+    case 18:
+    case "ERROR_CODE_INVALID_PATH":
+      return ErrorCode.ERROR_CODE_INVALID_PATH;
+    case 19:
+    case "ERROR_CODE_INVALID_ENVELOPE":
+      return ErrorCode.ERROR_CODE_INVALID_ENVELOPE;
+    case 20:
+    case "ERROR_CODE_ENCRYPTION_REQUIRED":
+      return ErrorCode.ERROR_CODE_ENCRYPTION_REQUIRED;
+    case 21:
+    case "ERROR_CODE_BURN_AFTER_READING":
+      return ErrorCode.ERROR_CODE_BURN_AFTER_READING;
+    case 22:
+    case "ERROR_CODE_INVALID_LIFETIME":
+      return ErrorCode.ERROR_CODE_INVALID_LIFETIME;
+    case -1:
+    case "UNRECOGNIZED":
+    default:
+      return ErrorCode.UNRECOGNIZED;
+  }
+}
+
+export function errorCodeToJSON(object: ErrorCode): string {
+  switch (object) {
+    case ErrorCode.ERROR_CODE_UNSPECIFIED:
+      return "ERROR_CODE_UNSPECIFIED";
+    case ErrorCode.ERROR_CODE_INTERNAL:
+      return "ERROR_CODE_INTERNAL";
+    case ErrorCode.ERROR_CODE_UNEXPECTED_MESSAGE:
+      return "ERROR_CODE_UNEXPECTED_MESSAGE";
+    case ErrorCode.ERROR_CODE_INVALID_CLIP:
+      return "ERROR_CODE_INVALID_CLIP";
+    case ErrorCode.ERROR_CODE_BUSY:
+      return "ERROR_CODE_BUSY";
+    case ErrorCode.ERROR_CODE_DISORDERED:
+      return "ERROR_CODE_DISORDERED";
+    case ErrorCode.ERROR_CODE_TOO_LARGE:
+      return "ERROR_CODE_TOO_LARGE";
+    case ErrorCode.ERROR_CODE_UNAUTHORIZED:
+      return "ERROR_CODE_UNAUTHORIZED";
+    case ErrorCode.ERROR_CODE_RATE_LIMITED:
+      return "ERROR_CODE_RATE_LIMITED";
+    case ErrorCode.ERROR_CODE_PROTOCOL_MISMATCH:
+      return "ERROR_CODE_PROTOCOL_MISMATCH";
+    case ErrorCode.ERROR_CODE_CHECKSUM_MISMATCH:
+      return "ERROR_CODE_CHECKSUM_MISMATCH";
+    case ErrorCode.ERROR_CODE_INVALID_RANGE:
+      return "ERROR_CODE_INVALID_RANGE";
+    case ErrorCode.ERROR_CODE_FILE_CHANGED:
+      return "ERROR_CODE_FILE_CHANGED";
+    case ErrorCode.ERROR_CODE_UPLOAD_ABORTED:
+      return "ERROR_CODE_UPLOAD_ABORTED";
+    case ErrorCode.ERROR_CODE_INVALID_CHUNK:
+      return "ERROR_CODE_INVALID_CHUNK";
+    case ErrorCode.ERROR_CODE_INVALID_VERSION:
+      return "ERROR_CODE_INVALID_VERSION";
+    case ErrorCode.ERROR_CODE_INVALID_ITEM:
+      return "ERROR_CODE_INVALID_ITEM";
+    case ErrorCode.ERROR_CODE_INVALID_MANIFEST:
+      return "ERROR_CODE_INVALID_MANIFEST";
+    case ErrorCode.ERROR_CODE_INVALID_PATH:
+      return "ERROR_CODE_INVALID_PATH";
+    case ErrorCode.ERROR_CODE_INVALID_ENVELOPE:
+      return "ERROR_CODE_INVALID_ENVELOPE";
+    case ErrorCode.ERROR_CODE_ENCRYPTION_REQUIRED:
+      return "ERROR_CODE_ENCRYPTION_REQUIRED";
+    case ErrorCode.ERROR_CODE_BURN_AFTER_READING:
+      return "ERROR_CODE_BURN_AFTER_READING";
+    case ErrorCode.ERROR_CODE_INVALID_LIFETIME:
+      return "ERROR_CODE_INVALID_LIFETIME";
+    case ErrorCode.UNRECOGNIZED:
+    default:
+      return "UNRECOGNIZED";
+  }
+}
+
 export interface Message {
   text?: Text | undefined;
   hdr?: FileHeader | undefined;
@@ -16,36 +322,223 @@ export interface Message {
   nextChunk?: NextChunk | undefined;
   ack?: Ack | undefined;
   err?: Error | undefined;
+  fetch?: Fetch | undefined;
+  hello?: Hello | undefined;
+  welcome?: Welcome | undefined;
+  cancel?: Cancel | undefined;
+  listVersions?: ListVersions | undefined;
+  versions?: Versions | undefined;
+  restoreVersion?: RestoreVersion | undefined;
+  addItem?: AddItem | undefined;
+  removeItem?: RemoveItem | undefined;
+  moveItem?: MoveItem | undefined;
+  items?: Items | undefined;
+  event?: Event | undefined;
+  consumed?: Consumed | undefined;
+  expiry?: Expiry | undefined;
 }
 
 export interface Text {
   data: string;
+  item: string;
+  /** replaces data in encrypted clips */
+  ciphertext: Uint8Array;
+  envelope: Envelope | undefined;
 }
 
 export interface FileHeader {
   filename: string;
   contentType: string;
   numChunks: number;
+  session: string;
+  window: number;
+  digest: Uint8Array;
+  item: string;
+  manifest: Manifest | undefined;
+  envelope: Envelope | undefined;
+}
+
+/**
+ * Envelope tells the clients of an encrypted clip how a text or a file was sealed with the key kept
+ * in the fragment of the URL of the clip, which never reaches the server. The key of the content is
+ * derived from that key with the kdf. A text is sealed with the nonce, the metadata of a file, such as
+ * its name, with the nonce, and chunk i with the nonce whose last 4 bytes are xored with i+1 big endian.
+ */
+export interface Envelope {
+  cipher: Cipher;
+  nonce: Uint8Array;
+  kdf: Kdf | undefined;
+  metadata: Uint8Array;
+}
+
+export interface Kdf {
+  algorithm: KdfAlgorithm;
+  salt: Uint8Array;
+  iterations: number;
+}
+
+export interface Entry {
+  path: string;
+  size: number;
+  mode: number;
+  dir: boolean;
+  start: number;
+  end: number;
+}
+
+export interface Manifest {
+  entries: Entry[];
 }
 
 export interface Chunk {
   index: number;
   data: Uint8Array;
+  hash: Uint8Array;
+  compressed: boolean;
 }
 
 export interface NextChunk {
+  index: number;
+  credits: number;
+}
+
+export interface Fetch {
+  session: string;
+  start: number;
+  end: number;
+  item: string;
+  path: string;
 }
 
 export interface Ack {
 }
 
+export interface Cancel {
+  session: string;
+}
+
+export interface ListVersions {
+}
+
+export interface Version {
+  id: number;
+  timestamp: number;
+  author: string;
+  text?: Text | undefined;
+  hdr?: FileHeader | undefined;
+  items: Item[];
+}
+
+export interface Versions {
+  versions: Version[];
+  current: number;
+}
+
+export interface RestoreVersion {
+  id: number;
+}
+
+export interface Item {
+  id: string;
+  text?: Text | undefined;
+  hdr?: FileHeader | undefined;
+}
+
+export interface Items {
+  items: Item[];
+}
+
+export interface AddItem {
+  text?: Text | undefined;
+  hdr?: FileHeader | undefined;
+}
+
+export interface RemoveItem {
+  id: string;
+}
+
+export interface MoveItem {
+  id: string;
+  position: number;
+}
+
+export interface Event {
+  version: number;
+  author: string;
+  timestamp: number;
+  items: Item[];
+}
+
+export interface Hello {
+  version: number;
+  capabilities: Capability[];
+  /** random id kept across reconnections, which tells uploaders apart */
+  clientId: string;
+}
+
+export interface Welcome {
+  version: number;
+  capabilities: Capability[];
+  encrypted: boolean;
+  readOnly: boolean;
+  burnAfterReading: boolean;
+  /** milliseconds the clip lives on once nobody is connected */
+  ttl: number;
+  /** unix milliseconds at which the clip ends, 0 for never */
+  expires: number;
+}
+
 export interface Error {
   fatal: boolean;
   desc: string;
+  code: ErrorCode;
+  busy?: Busy | undefined;
+}
+
+export interface Busy {
+  uploader: string;
+  filename: string;
+  received: number;
+  numChunks: number;
+}
+
+/**
+ * Consumed tells the uploader of an item of a burn after reading clip that another client has received it.
+ * The clip ends right after.
+ */
+export interface Consumed {
+  item: string;
+}
+
+/** Expiry warns clients which agreed on CAPABILITY_EXPIRY that the clip is about to end. */
+export interface Expiry {
+  /** unix milliseconds at which the clip ends, 0 once it no longer does soon */
+  timestamp: number;
 }
 
 function createBaseMessage(): Message {
-  return { text: undefined, hdr: undefined, chunk: undefined, nextChunk: undefined, ack: undefined, err: undefined };
+  return {
+    text: undefined,
+    hdr: undefined,
+    chunk: undefined,
+    nextChunk: undefined,
+    ack: undefined,
+    err: undefined,
+    fetch: undefined,
+    hello: undefined,
+    welcome: undefined,
+    cancel: undefined,
+    listVersions: undefined,
+    versions: undefined,
+    restoreVersion: undefined,
+    addItem: undefined,
+    removeItem: undefined,
+    moveItem: undefined,
+    items: undefined,
+    event: undefined,
+    consumed: undefined,
+    expiry: undefined,
+  };
 }
 
 export const Message: MessageFns<Message> = {
@@ -68,6 +561,48 @@ export const Message: MessageFns<Message> = {
     if (message.err !== undefined) {
       Error.encode(message.err, writer.uint32(50).fork()).join();
     }
+    if (message.fetch !== undefined) {
+      Fetch.encode(message.fetch, writer.uint32(58).fork()).join();
+    }
+    if (message.hello !== undefined) {
+      Hello.encode(message.hello, writer.uint32(66).fork()).join();
+    }
+    if (message.welcome !== undefined) {
+      Welcome.encode(message.welcome, writer.uint32(74).fork()).join();
+    }
+    if (message.cancel !== undefined) {
+      Cancel.encode(message.cancel, writer.uint32(82).fork()).join();
+    }
+    if (message.listVersions !== undefined) {
+      ListVersions.encode(message.listVersions, writer.uint32(90).fork()).join();
+    }
+    if (message.versions !== undefined) {
+      Versions.encode(message.versions, writer.uint32(98).fork()).join();
+    }
+    if (message.restoreVersion !== undefined) {
+      RestoreVersion.encode(message.restoreVersion, writer.uint32(106).fork()).join();
+    }
+    if (message.addItem !== undefined) {
+      AddItem.encode(message.addItem, writer.uint32(114).fork()).join();
+    }
+    if (message.removeItem !== undefined) {
+      RemoveItem.encode(message.removeItem, writer.uint32(122).fork()).join();
+    }
+    if (message.moveItem !== undefined) {
+      MoveItem.encode(message.moveItem, writer.uint32(130).fork()).join();
+    }
+    if (message.items !== undefined) {
+      Items.encode(message.items, writer.uint32(138).fork()).join();
+    }
+    if (message.event !== undefined) {
+      Event.encode(message.event, writer.uint32(146).fork()).join();
+    }
+    if (message.consumed !== undefined) {
+      Consumed.encode(message.consumed, writer.uint32(154).fork()).join();
+    }
+    if (message.expiry !== undefined) {
+      Expiry.encode(message.expiry, writer.uint32(162).fork()).join();
+    }
     return writer;
   },
 
@@ -126,6 +661,118 @@ export const Message: MessageFns<Message> = {
           message.err = Error.decode(reader, reader.uint32());
           continue;
         }
+        case 7: {
+          if (tag !== 58) {
+            break;
+          }
+
+          message.fetch = Fetch.decode(reader, reader.uint32());
+          continue;
+        }
+        case 8: {
+          if (tag !== 66) {
+            break;
+          }
+
+          message.hello = Hello.decode(reader, reader.uint32());
+          continue;
+        }
+        case 9: {
+          if (tag !== 74) {
+            break;
+          }
+
+          message.welcome = Welcome.decode(reader, reader.uint32());
+          continue;
+        }
+        case 10: {
+          if (tag !== 82) {
+            break;
+          }
+
+          message.cancel = Cancel.decode(reader, reader.uint32());
+          continue;
+        }
+        case 11: {
+          if (tag !== 90) {
+            break;
+          }
+
+          message.listVersions = ListVersions.decode(reader, reader.uint32());
+          continue;
+        }
+        case 12: {
+          if (tag !== 98) {
+            break;
+          }
+
+          message.versions = Versions.decode(reader, reader.uint32());
+          continue;
+        }
+        case 13: {
+          if (tag !== 106) {
+            break;
+          }
+
+          message.restoreVersion = RestoreVersion.decode(reader, reader.uint32());
+          continue;
+        }
+        case 14: {
+          if (tag !== 114) {
+            break;
+          }
+
+          message.addItem = AddItem.decode(reader, reader.uint32());
+          continue;
+        }
+        case 15: {
+          if (tag !== 122) {
+            break;
+          }
+
+          message.removeItem = RemoveItem.decode(reader, reader.uint32());
+          continue;
+        }
+        case 16: {
+          if (tag !== 130) {
+            break;
+          }
+
+          message.moveItem = MoveItem.decode(reader, reader.uint32());
+          continue;
+        }
+        case 17: {
+          if (tag !== 138) {
+            break;
+          }
+
+          message.items = Items.decode(reader, reader.uint32());
+          continue;
+        }
+        case 18: {
+          if (tag !== 146) {
+            break;
+          }
+
+          message.event = Event.decode(reader, reader.uint32());
+          continue;
+        }
+        case 19: {
+          if (tag !== 154) {
+            break;
+          }
+
+          message.consumed = Consumed.decode(reader, reader.uint32());
+          continue;
+        }
+        case 20: {
+          if (tag !== 162) {
+            break;
+          }
+
+          message.expiry = Expiry.decode(reader, reader.uint32());
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -143,6 +790,20 @@ export const Message: MessageFns<Message> = {
       nextChunk: isSet(object.nextChunk) ? NextChunk.fromJSON(object.nextChunk) : undefined,
       ack: isSet(object.ack) ? Ack.fromJSON(object.ack) : undefined,
       err: isSet(object.err) ? Error.fromJSON(object.err) : undefined,
+      fetch: isSet(object.fetch) ? Fetch.fromJSON(object.fetch) : undefined,
+      hello: isSet(object.hello) ? Hello.fromJSON(object.hello) : undefined,
+      welcome: isSet(object.welcome) ? Welcome.fromJSON(object.welcome) : undefined,
+      cancel: isSet(object.cancel) ? Cancel.fromJSON(object.cancel) : undefined,
+      listVersions: isSet(object.listVersions) ? ListVersions.fromJSON(object.listVersions) : undefined,
+      versions: isSet(object.versions) ? Versions.fromJSON(object.versions) : undefined,
+      restoreVersion: isSet(object.restoreVersion) ? RestoreVersion.fromJSON(object.restoreVersion) : undefined,
+      addItem: isSet(object.addItem) ? AddItem.fromJSON(object.addItem) : undefined,
+      removeItem: isSet(object.removeItem) ? RemoveItem.fromJSON(object.removeItem) : undefined,
+      moveItem: isSet(object.moveItem) ? MoveItem.fromJSON(object.moveItem) : undefined,
+      items: isSet(object.items) ? Items.fromJSON(object.items) : undefined,
+      event: isSet(object.event) ? Event.fromJSON(object.event) : undefined,
+      consumed: isSet(object.consumed) ? Consumed.fromJSON(object.consumed) : undefined,
+      expiry: isSet(object.expiry) ? Expiry.fromJSON(object.expiry) : undefined,
     };
   },
 
@@ -166,6 +827,48 @@ export const Message: MessageFns<Message> = {
     if (message.err !== undefined) {
       obj.err = Error.toJSON(message.err);
     }
+    if (message.fetch !== undefined) {
+      obj.fetch = Fetch.toJSON(message.fetch);
+    }
+    if (message.hello !== undefined) {
+      obj.hello = Hello.toJSON(message.hello);
+    }
+    if (message.welcome !== undefined) {
+      obj.welcome = Welcome.toJSON(message.welcome);
+    }
+    if (message.cancel !== undefined) {
+      obj.cancel = Cancel.toJSON(message.cancel);
+    }
+    if (message.listVersions !== undefined) {
+      obj.listVersions = ListVersions.toJSON(message.listVersions);
+    }
+    if (message.versions !== undefined) {
+      obj.versions = Versions.toJSON(message.versions);
+    }
+    if (message.restoreVersion !== undefined) {
+      obj.restoreVersion = RestoreVersion.toJSON(message.restoreVersion);
+    }
+    if (message.addItem !== undefined) {
+      obj.addItem = AddItem.toJSON(message.addItem);
+    }
+    if (message.removeItem !== undefined) {
+      obj.removeItem = RemoveItem.toJSON(message.removeItem);
+    }
+    if (message.moveItem !== undefined) {
+      obj.moveItem = MoveItem.toJSON(message.moveItem);
+    }
+    if (message.items !== undefined) {
+      obj.items = Items.toJSON(message.items);
+    }
+    if (message.event !== undefined) {
+      obj.event = Event.toJSON(message.event);
+    }
+    if (message.consumed !== undefined) {
+      obj.consumed = Consumed.toJSON(message.consumed);
+    }
+    if (message.expiry !== undefined) {
+      obj.expiry = Expiry.toJSON(message.expiry);
+    }
     return obj;
   },
 
@@ -182,12 +885,46 @@ export const Message: MessageFns<Message> = {
       : undefined;
     message.ack = (object.ack !== undefined && object.ack !== null) ? Ack.fromPartial(object.ack) : undefined;
     message.err = (object.err !== undefined && object.err !== null) ? Error.fromPartial(object.err) : undefined;
+    message.fetch = (object.fetch !== undefined && object.fetch !== null) ? Fetch.fromPartial(object.fetch) : undefined;
+    message.hello = (object.hello !== undefined && object.hello !== null) ? Hello.fromPartial(object.hello) : undefined;
+    message.welcome = (object.welcome !== undefined && object.welcome !== null)
+      ? Welcome.fromPartial(object.welcome)
+      : undefined;
+    message.cancel = (object.cancel !== undefined && object.cancel !== null)
+      ? Cancel.fromPartial(object.cancel)
+      : undefined;
+    message.listVersions = (object.listVersions !== undefined && object.listVersions !== null)
+      ? ListVersions.fromPartial(object.listVersions)
+      : undefined;
+    message.versions = (object.versions !== undefined && object.versions !== null)
+      ? Versions.fromPartial(object.versions)
+      : undefined;
+    message.restoreVersion = (object.restoreVersion !== undefined && object.restoreVersion !== null)
+      ? RestoreVersion.fromPartial(object.restoreVersion)
+      : undefined;
+    message.addItem = (object.addItem !== undefined && object.addItem !== null)
+      ? AddItem.fromPartial(object.addItem)
+      : undefined;
+    message.removeItem = (object.removeItem !== undefined && object.removeItem !== null)
+      ? RemoveItem.fromPartial(object.removeItem)
+      : undefined;
+    message.moveItem = (object.moveItem !== undefined && object.moveItem !== null)
+      ? MoveItem.fromPartial(object.moveItem)
+      : undefined;
+    message.items = (object.items !== undefined && object.items !== null) ? Items.fromPartial(object.items) : undefined;
+    message.event = (object.event !== undefined && object.event !== null) ? Event.fromPartial(object.event) : undefined;
+    message.consumed = (object.consumed !== undefined && object.consumed !== null)
+      ? Consumed.fromPartial(object.consumed)
+      : undefined;
+    message.expiry = (object.expiry !== undefined && object.expiry !== null)
+      ? Expiry.fromPartial(object.expiry)
+      : undefined;
     return message;
   },
 };
 
 function createBaseText(): Text {
-  return { data: "" };
+  return { data: "", item: "", ciphertext: new Uint8Array(0), envelope: undefined };
 }
 
 export const Text: MessageFns<Text> = {
@@ -195,6 +932,15 @@ export const Text: MessageFns<Text> = {
     if (message.data !== "") {
       writer.uint32(10).string(message.data);
     }
+    if (message.item !== "") {
+      writer.uint32(18).string(message.item);
+    }
+    if (message.ciphertext.length !== 0) {
+      writer.uint32(26).bytes(message.ciphertext);
+    }
+    if (message.envelope !== undefined) {
+      Envelope.encode(message.envelope, writer.uint32(34).fork()).join();
+    }
     return writer;
   },
 
@@ -213,6 +959,30 @@ export const Text: MessageFns<Text> = {
           message.data = reader.string();
           continue;
         }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.item = reader.string();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.ciphertext = reader.bytes();
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.envelope = Envelope.decode(reader, reader.uint32());
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -223,7 +993,12 @@ export const Text: MessageFns<Text> = {
   },
 
   fromJSON(object: any): Text {
-    return { data: isSet(object.data) ? globalThis.String(object.data) : "" };
+    return {
+      data: isSet(object.data) ? globalThis.String(object.data) : "",
+      item: isSet(object.item) ? globalThis.String(object.item) : "",
+      ciphertext: isSet(object.ciphertext) ? bytesFromBase64(object.ciphertext) : new Uint8Array(0),
+      envelope: isSet(object.envelope) ? Envelope.fromJSON(object.envelope) : undefined,
+    };
   },
 
   toJSON(message: Text): unknown {
@@ -231,6 +1006,15 @@ export const Text: MessageFns<Text> = {
     if (message.data !== "") {
       obj.data = message.data;
     }
+    if (message.item !== "") {
+      obj.item = message.item;
+    }
+    if (message.ciphertext.length !== 0) {
+      obj.ciphertext = base64FromBytes(message.ciphertext);
+    }
+    if (message.envelope !== undefined) {
+      obj.envelope = Envelope.toJSON(message.envelope);
+    }
     return obj;
   },
 
@@ -240,12 +1024,27 @@ export const Text: MessageFns<Text> = {
   fromPartial<I extends Exact<DeepPartial<Text>, I>>(object: I): Text {
     const message = createBaseText();
     message.data = object.data ?? "";
+    message.item = object.item ?? "";
+    message.ciphertext = object.ciphertext ?? new Uint8Array(0);
+    message.envelope = (object.envelope !== undefined && object.envelope !== null)
+      ? Envelope.fromPartial(object.envelope)
+      : undefined;
     return message;
   },
 };
 
 function createBaseFileHeader(): FileHeader {
-  return { filename: "", contentType: "", numChunks: 0 };
+  return {
+    filename: "",
+    contentType: "",
+    numChunks: 0,
+    session: "",
+    window: 0,
+    digest: new Uint8Array(0),
+    item: "",
+    manifest: undefined,
+    envelope: undefined,
+  };
 }
 
 export const FileHeader: MessageFns<FileHeader> = {
@@ -259,6 +1058,24 @@ export const FileHeader: MessageFns<FileHeader> = {
     if (message.numChunks !== 0) {
       writer.uint32(24).int32(message.numChunks);
     }
+    if (message.session !== "") {
+      writer.uint32(34).string(message.session);
+    }
+    if (message.window !== 0) {
+      writer.uint32(40).int32(message.window);
+    }
+    if (message.digest.length !== 0) {
+      writer.uint32(50).bytes(message.digest);
+    }
+    if (message.item !== "") {
+      writer.uint32(58).string(message.item);
+    }
+    if (message.manifest !== undefined) {
+      Manifest.encode(message.manifest, writer.uint32(66).fork()).join();
+    }
+    if (message.envelope !== undefined) {
+      Envelope.encode(message.envelope, writer.uint32(74).fork()).join();
+    }
     return writer;
   },
 
@@ -293,68 +1110,2034 @@ export const FileHeader: MessageFns<FileHeader> = {
           message.numChunks = reader.int32();
           continue;
         }
-      }
-      if ((tag & 7) === 4 || tag === 0) {
-        break;
-      }
-      reader.skip(tag & 7);
-    }
-    return message;
-  },
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
 
-  fromJSON(object: any): FileHeader {
-    return {
-      filename: isSet(object.filename) ? globalThis.String(object.filename) : "",
-      contentType: isSet(object.contentType) ? globalThis.String(object.contentType) : "",
-      numChunks: isSet(object.numChunks) ? globalThis.Number(object.numChunks) : 0,
-    };
-  },
+          message.session = reader.string();
+          continue;
+        }
+        case 5: {
+          if (tag !== 40) {
+            break;
+          }
 
-  toJSON(message: FileHeader): unknown {
-    const obj: any = {};
-    if (message.filename !== "") {
-      obj.filename = message.filename;
-    }
+          message.window = reader.int32();
+          continue;
+        }
+        case 6: {
+          if (tag !== 50) {
+            break;
+          }
+
+          message.digest = reader.bytes();
+          continue;
+        }
+        case 7: {
+          if (tag !== 58) {
+            break;
+          }
+
+          message.item = reader.string();
+          continue;
+        }
+        case 8: {
+          if (tag !== 66) {
+            break;
+          }
+
+          message.manifest = Manifest.decode(reader, reader.uint32());
+          continue;
+        }
+        case 9: {
+          if (tag !== 74) {
+            break;
+          }
+
+          message.envelope = Envelope.decode(reader, reader.uint32());
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): FileHeader {
+    return {
+      filename: isSet(object.filename) ? globalThis.String(object.filename) : "",
+      contentType: isSet(object.contentType) ? globalThis.String(object.contentType) : "",
+      numChunks: isSet(object.numChunks) ? globalThis.Number(object.numChunks) : 0,
+      session: isSet(object.session) ? globalThis.String(object.session) : "",
+      window: isSet(object.window) ? globalThis.Number(object.window) : 0,
+      digest: isSet(object.digest) ? bytesFromBase64(object.digest) : new Uint8Array(0),
+      item: isSet(object.item) ? globalThis.String(object.item) : "",
+      manifest: isSet(object.manifest) ? Manifest.fromJSON(object.manifest) : undefined,
+      envelope: isSet(object.envelope) ? Envelope.fromJSON(object.envelope) : undefined,
+    };
+  },
+
+  toJSON(message: FileHeader): unknown {
+    const obj: any = {};
+    if (message.filename !== "") {
+      obj.filename = message.filename;
+    }
     if (message.contentType !== "") {
       obj.contentType = message.contentType;
     }
-    if (message.numChunks !== 0) {
-      obj.numChunks = Math.round(message.numChunks);
+    if (message.numChunks !== 0) {
+      obj.numChunks = Math.round(message.numChunks);
+    }
+    if (message.session !== "") {
+      obj.session = message.session;
+    }
+    if (message.window !== 0) {
+      obj.window = Math.round(message.window);
+    }
+    if (message.digest.length !== 0) {
+      obj.digest = base64FromBytes(message.digest);
+    }
+    if (message.item !== "") {
+      obj.item = message.item;
+    }
+    if (message.manifest !== undefined) {
+      obj.manifest = Manifest.toJSON(message.manifest);
+    }
+    if (message.envelope !== undefined) {
+      obj.envelope = Envelope.toJSON(message.envelope);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<FileHeader>, I>>(base?: I): FileHeader {
+    return FileHeader.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<FileHeader>, I>>(object: I): FileHeader {
+    const message = createBaseFileHeader();
+    message.filename = object.filename ?? "";
+    message.contentType = object.contentType ?? "";
+    message.numChunks = object.numChunks ?? 0;
+    message.session = object.session ?? "";
+    message.window = object.window ?? 0;
+    message.digest = object.digest ?? new Uint8Array(0);
+    message.item = object.item ?? "";
+    message.manifest = (object.manifest !== undefined && object.manifest !== null)
+      ? Manifest.fromPartial(object.manifest)
+      : undefined;
+    message.envelope = (object.envelope !== undefined && object.envelope !== null)
+      ? Envelope.fromPartial(object.envelope)
+      : undefined;
+    return message;
+  },
+};
+
+function createBaseEnvelope(): Envelope {
+  return { cipher: 0, nonce: new Uint8Array(0), kdf: undefined, metadata: new Uint8Array(0) };
+}
+
+export const Envelope: MessageFns<Envelope> = {
+  encode(message: Envelope, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.cipher !== 0) {
+      writer.uint32(8).int32(message.cipher);
+    }
+    if (message.nonce.length !== 0) {
+      writer.uint32(18).bytes(message.nonce);
+    }
+    if (message.kdf !== undefined) {
+      Kdf.encode(message.kdf, writer.uint32(26).fork()).join();
+    }
+    if (message.metadata.length !== 0) {
+      writer.uint32(34).bytes(message.metadata);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Envelope {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseEnvelope();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.cipher = reader.int32() as any;
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.nonce = reader.bytes();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.kdf = Kdf.decode(reader, reader.uint32());
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.metadata = reader.bytes();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Envelope {
+    return {
+      cipher: isSet(object.cipher) ? cipherFromJSON(object.cipher) : 0,
+      nonce: isSet(object.nonce) ? bytesFromBase64(object.nonce) : new Uint8Array(0),
+      kdf: isSet(object.kdf) ? Kdf.fromJSON(object.kdf) : undefined,
+      metadata: isSet(object.metadata) ? bytesFromBase64(object.metadata) : new Uint8Array(0),
+    };
+  },
+
+  toJSON(message: Envelope): unknown {
+    const obj: any = {};
+    if (message.cipher !== 0) {
+      obj.cipher = cipherToJSON(message.cipher);
+    }
+    if (message.nonce.length !== 0) {
+      obj.nonce = base64FromBytes(message.nonce);
+    }
+    if (message.kdf !== undefined) {
+      obj.kdf = Kdf.toJSON(message.kdf);
+    }
+    if (message.metadata.length !== 0) {
+      obj.metadata = base64FromBytes(message.metadata);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Envelope>, I>>(base?: I): Envelope {
+    return Envelope.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Envelope>, I>>(object: I): Envelope {
+    const message = createBaseEnvelope();
+    message.cipher = object.cipher ?? 0;
+    message.nonce = object.nonce ?? new Uint8Array(0);
+    message.kdf = (object.kdf !== undefined && object.kdf !== null) ? Kdf.fromPartial(object.kdf) : undefined;
+    message.metadata = object.metadata ?? new Uint8Array(0);
+    return message;
+  },
+};
+
+function createBaseKdf(): Kdf {
+  return { algorithm: 0, salt: new Uint8Array(0), iterations: 0 };
+}
+
+export const Kdf: MessageFns<Kdf> = {
+  encode(message: Kdf, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.algorithm !== 0) {
+      writer.uint32(8).int32(message.algorithm);
+    }
+    if (message.salt.length !== 0) {
+      writer.uint32(18).bytes(message.salt);
+    }
+    if (message.iterations !== 0) {
+      writer.uint32(24).uint32(message.iterations);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Kdf {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseKdf();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.algorithm = reader.int32() as any;
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.salt = reader.bytes();
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.iterations = reader.uint32();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Kdf {
+    return {
+      algorithm: isSet(object.algorithm) ? kdfAlgorithmFromJSON(object.algorithm) : 0,
+      salt: isSet(object.salt) ? bytesFromBase64(object.salt) : new Uint8Array(0),
+      iterations: isSet(object.iterations) ? globalThis.Number(object.iterations) : 0,
+    };
+  },
+
+  toJSON(message: Kdf): unknown {
+    const obj: any = {};
+    if (message.algorithm !== 0) {
+      obj.algorithm = kdfAlgorithmToJSON(message.algorithm);
+    }
+    if (message.salt.length !== 0) {
+      obj.salt = base64FromBytes(message.salt);
+    }
+    if (message.iterations !== 0) {
+      obj.iterations = Math.round(message.iterations);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Kdf>, I>>(base?: I): Kdf {
+    return Kdf.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Kdf>, I>>(object: I): Kdf {
+    const message = createBaseKdf();
+    message.algorithm = object.algorithm ?? 0;
+    message.salt = object.salt ?? new Uint8Array(0);
+    message.iterations = object.iterations ?? 0;
+    return message;
+  },
+};
+
+function createBaseEntry(): Entry {
+  return { path: "", size: 0, mode: 0, dir: false, start: 0, end: 0 };
+}
+
+export const Entry: MessageFns<Entry> = {
+  encode(message: Entry, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.path !== "") {
+      writer.uint32(10).string(message.path);
+    }
+    if (message.size !== 0) {
+      writer.uint32(16).int64(message.size);
+    }
+    if (message.mode !== 0) {
+      writer.uint32(24).uint32(message.mode);
+    }
+    if (message.dir !== false) {
+      writer.uint32(32).bool(message.dir);
+    }
+    if (message.start !== 0) {
+      writer.uint32(40).int32(message.start);
+    }
+    if (message.end !== 0) {
+      writer.uint32(48).int32(message.end);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Entry {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseEntry();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.path = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.size = longToNumber(reader.int64());
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.mode = reader.uint32();
+          continue;
+        }
+        case 4: {
+          if (tag !== 32) {
+            break;
+          }
+
+          message.dir = reader.bool();
+          continue;
+        }
+        case 5: {
+          if (tag !== 40) {
+            break;
+          }
+
+          message.start = reader.int32();
+          continue;
+        }
+        case 6: {
+          if (tag !== 48) {
+            break;
+          }
+
+          message.end = reader.int32();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Entry {
+    return {
+      path: isSet(object.path) ? globalThis.String(object.path) : "",
+      size: isSet(object.size) ? globalThis.Number(object.size) : 0,
+      mode: isSet(object.mode) ? globalThis.Number(object.mode) : 0,
+      dir: isSet(object.dir) ? globalThis.Boolean(object.dir) : false,
+      start: isSet(object.start) ? globalThis.Number(object.start) : 0,
+      end: isSet(object.end) ? globalThis.Number(object.end) : 0,
+    };
+  },
+
+  toJSON(message: Entry): unknown {
+    const obj: any = {};
+    if (message.path !== "") {
+      obj.path = message.path;
+    }
+    if (message.size !== 0) {
+      obj.size = Math.round(message.size);
+    }
+    if (message.mode !== 0) {
+      obj.mode = Math.round(message.mode);
+    }
+    if (message.dir !== false) {
+      obj.dir = message.dir;
+    }
+    if (message.start !== 0) {
+      obj.start = Math.round(message.start);
+    }
+    if (message.end !== 0) {
+      obj.end = Math.round(message.end);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Entry>, I>>(base?: I): Entry {
+    return Entry.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Entry>, I>>(object: I): Entry {
+    const message = createBaseEntry();
+    message.path = object.path ?? "";
+    message.size = object.size ?? 0;
+    message.mode = object.mode ?? 0;
+    message.dir = object.dir ?? false;
+    message.start = object.start ?? 0;
+    message.end = object.end ?? 0;
+    return message;
+  },
+};
+
+function createBaseManifest(): Manifest {
+  return { entries: [] };
+}
+
+export const Manifest: MessageFns<Manifest> = {
+  encode(message: Manifest, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    for (const v of message.entries) {
+      Entry.encode(v!, writer.uint32(10).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Manifest {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseManifest();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.entries.push(Entry.decode(reader, reader.uint32()));
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Manifest {
+    return {
+      entries: globalThis.Array.isArray(object?.entries) ? object.entries.map((e: any) => Entry.fromJSON(e)) : [],
+    };
+  },
+
+  toJSON(message: Manifest): unknown {
+    const obj: any = {};
+    if (message.entries?.length) {
+      obj.entries = message.entries.map((e) => Entry.toJSON(e));
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Manifest>, I>>(base?: I): Manifest {
+    return Manifest.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Manifest>, I>>(object: I): Manifest {
+    const message = createBaseManifest();
+    message.entries = object.entries?.map((e) => Entry.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseChunk(): Chunk {
+  return { index: 0, data: new Uint8Array(0), hash: new Uint8Array(0), compressed: false };
+}
+
+export const Chunk: MessageFns<Chunk> = {
+  encode(message: Chunk, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.index !== 0) {
+      writer.uint32(8).int32(message.index);
+    }
+    if (message.data.length !== 0) {
+      writer.uint32(18).bytes(message.data);
+    }
+    if (message.hash.length !== 0) {
+      writer.uint32(26).bytes(message.hash);
+    }
+    if (message.compressed !== false) {
+      writer.uint32(32).bool(message.compressed);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Chunk {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseChunk();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.index = reader.int32();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.data = reader.bytes();
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.hash = reader.bytes();
+          continue;
+        }
+        case 4: {
+          if (tag !== 32) {
+            break;
+          }
+
+          message.compressed = reader.bool();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Chunk {
+    return {
+      index: isSet(object.index) ? globalThis.Number(object.index) : 0,
+      data: isSet(object.data) ? bytesFromBase64(object.data) : new Uint8Array(0),
+      hash: isSet(object.hash) ? bytesFromBase64(object.hash) : new Uint8Array(0),
+      compressed: isSet(object.compressed) ? globalThis.Boolean(object.compressed) : false,
+    };
+  },
+
+  toJSON(message: Chunk): unknown {
+    const obj: any = {};
+    if (message.index !== 0) {
+      obj.index = Math.round(message.index);
+    }
+    if (message.data.length !== 0) {
+      obj.data = base64FromBytes(message.data);
+    }
+    if (message.hash.length !== 0) {
+      obj.hash = base64FromBytes(message.hash);
+    }
+    if (message.compressed !== false) {
+      obj.compressed = message.compressed;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Chunk>, I>>(base?: I): Chunk {
+    return Chunk.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Chunk>, I>>(object: I): Chunk {
+    const message = createBaseChunk();
+    message.index = object.index ?? 0;
+    message.data = object.data ?? new Uint8Array(0);
+    message.hash = object.hash ?? new Uint8Array(0);
+    message.compressed = object.compressed ?? false;
+    return message;
+  },
+};
+
+function createBaseNextChunk(): NextChunk {
+  return { index: 0, credits: 0 };
+}
+
+export const NextChunk: MessageFns<NextChunk> = {
+  encode(message: NextChunk, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.index !== 0) {
+      writer.uint32(8).int32(message.index);
+    }
+    if (message.credits !== 0) {
+      writer.uint32(16).int32(message.credits);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): NextChunk {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseNextChunk();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.index = reader.int32();
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.credits = reader.int32();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): NextChunk {
+    return {
+      index: isSet(object.index) ? globalThis.Number(object.index) : 0,
+      credits: isSet(object.credits) ? globalThis.Number(object.credits) : 0,
+    };
+  },
+
+  toJSON(message: NextChunk): unknown {
+    const obj: any = {};
+    if (message.index !== 0) {
+      obj.index = Math.round(message.index);
+    }
+    if (message.credits !== 0) {
+      obj.credits = Math.round(message.credits);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<NextChunk>, I>>(base?: I): NextChunk {
+    return NextChunk.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<NextChunk>, I>>(object: I): NextChunk {
+    const message = createBaseNextChunk();
+    message.index = object.index ?? 0;
+    message.credits = object.credits ?? 0;
+    return message;
+  },
+};
+
+function createBaseFetch(): Fetch {
+  return { session: "", start: 0, end: 0, item: "", path: "" };
+}
+
+export const Fetch: MessageFns<Fetch> = {
+  encode(message: Fetch, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.session !== "") {
+      writer.uint32(10).string(message.session);
+    }
+    if (message.start !== 0) {
+      writer.uint32(16).int32(message.start);
+    }
+    if (message.end !== 0) {
+      writer.uint32(24).int32(message.end);
+    }
+    if (message.item !== "") {
+      writer.uint32(34).string(message.item);
+    }
+    if (message.path !== "") {
+      writer.uint32(42).string(message.path);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Fetch {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseFetch();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.session = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.start = reader.int32();
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.end = reader.int32();
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.item = reader.string();
+          continue;
+        }
+        case 5: {
+          if (tag !== 42) {
+            break;
+          }
+
+          message.path = reader.string();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Fetch {
+    return {
+      session: isSet(object.session) ? globalThis.String(object.session) : "",
+      start: isSet(object.start) ? globalThis.Number(object.start) : 0,
+      end: isSet(object.end) ? globalThis.Number(object.end) : 0,
+      item: isSet(object.item) ? globalThis.String(object.item) : "",
+      path: isSet(object.path) ? globalThis.String(object.path) : "",
+    };
+  },
+
+  toJSON(message: Fetch): unknown {
+    const obj: any = {};
+    if (message.session !== "") {
+      obj.session = message.session;
+    }
+    if (message.start !== 0) {
+      obj.start = Math.round(message.start);
+    }
+    if (message.end !== 0) {
+      obj.end = Math.round(message.end);
+    }
+    if (message.item !== "") {
+      obj.item = message.item;
+    }
+    if (message.path !== "") {
+      obj.path = message.path;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Fetch>, I>>(base?: I): Fetch {
+    return Fetch.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Fetch>, I>>(object: I): Fetch {
+    const message = createBaseFetch();
+    message.session = object.session ?? "";
+    message.start = object.start ?? 0;
+    message.end = object.end ?? 0;
+    message.item = object.item ?? "";
+    message.path = object.path ?? "";
+    return message;
+  },
+};
+
+function createBaseAck(): Ack {
+  return {};
+}
+
+export const Ack: MessageFns<Ack> = {
+  encode(_: Ack, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Ack {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseAck();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(_: any): Ack {
+    return {};
+  },
+
+  toJSON(_: Ack): unknown {
+    const obj: any = {};
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Ack>, I>>(base?: I): Ack {
+    return Ack.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Ack>, I>>(_: I): Ack {
+    const message = createBaseAck();
+    return message;
+  },
+};
+
+function createBaseCancel(): Cancel {
+  return { session: "" };
+}
+
+export const Cancel: MessageFns<Cancel> = {
+  encode(message: Cancel, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.session !== "") {
+      writer.uint32(10).string(message.session);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Cancel {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseCancel();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.session = reader.string();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Cancel {
+    return { session: isSet(object.session) ? globalThis.String(object.session) : "" };
+  },
+
+  toJSON(message: Cancel): unknown {
+    const obj: any = {};
+    if (message.session !== "") {
+      obj.session = message.session;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Cancel>, I>>(base?: I): Cancel {
+    return Cancel.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Cancel>, I>>(object: I): Cancel {
+    const message = createBaseCancel();
+    message.session = object.session ?? "";
+    return message;
+  },
+};
+
+function createBaseListVersions(): ListVersions {
+  return {};
+}
+
+export const ListVersions: MessageFns<ListVersions> = {
+  encode(_: ListVersions, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): ListVersions {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseListVersions();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(_: any): ListVersions {
+    return {};
+  },
+
+  toJSON(_: ListVersions): unknown {
+    const obj: any = {};
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<ListVersions>, I>>(base?: I): ListVersions {
+    return ListVersions.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<ListVersions>, I>>(_: I): ListVersions {
+    const message = createBaseListVersions();
+    return message;
+  },
+};
+
+function createBaseVersion(): Version {
+  return { id: 0, timestamp: 0, author: "", text: undefined, hdr: undefined, items: [] };
+}
+
+export const Version: MessageFns<Version> = {
+  encode(message: Version, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.id !== 0) {
+      writer.uint32(8).int32(message.id);
+    }
+    if (message.timestamp !== 0) {
+      writer.uint32(16).int64(message.timestamp);
+    }
+    if (message.author !== "") {
+      writer.uint32(26).string(message.author);
+    }
+    if (message.text !== undefined) {
+      Text.encode(message.text, writer.uint32(34).fork()).join();
+    }
+    if (message.hdr !== undefined) {
+      FileHeader.encode(message.hdr, writer.uint32(42).fork()).join();
+    }
+    for (const v of message.items) {
+      Item.encode(v!, writer.uint32(50).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Version {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseVersion();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.id = reader.int32();
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.timestamp = longToNumber(reader.int64());
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.author = reader.string();
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.text = Text.decode(reader, reader.uint32());
+          continue;
+        }
+        case 5: {
+          if (tag !== 42) {
+            break;
+          }
+
+          message.hdr = FileHeader.decode(reader, reader.uint32());
+          continue;
+        }
+        case 6: {
+          if (tag !== 50) {
+            break;
+          }
+
+          message.items.push(Item.decode(reader, reader.uint32()));
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Version {
+    return {
+      id: isSet(object.id) ? globalThis.Number(object.id) : 0,
+      timestamp: isSet(object.timestamp) ? globalThis.Number(object.timestamp) : 0,
+      author: isSet(object.author) ? globalThis.String(object.author) : "",
+      text: isSet(object.text) ? Text.fromJSON(object.text) : undefined,
+      hdr: isSet(object.hdr) ? FileHeader.fromJSON(object.hdr) : undefined,
+      items: globalThis.Array.isArray(object?.items) ? object.items.map((e: any) => Item.fromJSON(e)) : [],
+    };
+  },
+
+  toJSON(message: Version): unknown {
+    const obj: any = {};
+    if (message.id !== 0) {
+      obj.id = Math.round(message.id);
+    }
+    if (message.timestamp !== 0) {
+      obj.timestamp = Math.round(message.timestamp);
+    }
+    if (message.author !== "") {
+      obj.author = message.author;
+    }
+    if (message.text !== undefined) {
+      obj.text = Text.toJSON(message.text);
+    }
+    if (message.hdr !== undefined) {
+      obj.hdr = FileHeader.toJSON(message.hdr);
+    }
+    if (message.items?.length) {
+      obj.items = message.items.map((e) => Item.toJSON(e));
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Version>, I>>(base?: I): Version {
+    return Version.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Version>, I>>(object: I): Version {
+    const message = createBaseVersion();
+    message.id = object.id ?? 0;
+    message.timestamp = object.timestamp ?? 0;
+    message.author = object.author ?? "";
+    message.text = (object.text !== undefined && object.text !== null) ? Text.fromPartial(object.text) : undefined;
+    message.hdr = (object.hdr !== undefined && object.hdr !== null) ? FileHeader.fromPartial(object.hdr) : undefined;
+    message.items = object.items?.map((e) => Item.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseVersions(): Versions {
+  return { versions: [], current: 0 };
+}
+
+export const Versions: MessageFns<Versions> = {
+  encode(message: Versions, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    for (const v of message.versions) {
+      Version.encode(v!, writer.uint32(10).fork()).join();
+    }
+    if (message.current !== 0) {
+      writer.uint32(16).int32(message.current);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Versions {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseVersions();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.versions.push(Version.decode(reader, reader.uint32()));
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.current = reader.int32();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Versions {
+    return {
+      versions: globalThis.Array.isArray(object?.versions) ? object.versions.map((e: any) => Version.fromJSON(e)) : [],
+      current: isSet(object.current) ? globalThis.Number(object.current) : 0,
+    };
+  },
+
+  toJSON(message: Versions): unknown {
+    const obj: any = {};
+    if (message.versions?.length) {
+      obj.versions = message.versions.map((e) => Version.toJSON(e));
+    }
+    if (message.current !== 0) {
+      obj.current = Math.round(message.current);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Versions>, I>>(base?: I): Versions {
+    return Versions.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Versions>, I>>(object: I): Versions {
+    const message = createBaseVersions();
+    message.versions = object.versions?.map((e) => Version.fromPartial(e)) || [];
+    message.current = object.current ?? 0;
+    return message;
+  },
+};
+
+function createBaseRestoreVersion(): RestoreVersion {
+  return { id: 0 };
+}
+
+export const RestoreVersion: MessageFns<RestoreVersion> = {
+  encode(message: RestoreVersion, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.id !== 0) {
+      writer.uint32(8).int32(message.id);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): RestoreVersion {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseRestoreVersion();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.id = reader.int32();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): RestoreVersion {
+    return { id: isSet(object.id) ? globalThis.Number(object.id) : 0 };
+  },
+
+  toJSON(message: RestoreVersion): unknown {
+    const obj: any = {};
+    if (message.id !== 0) {
+      obj.id = Math.round(message.id);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<RestoreVersion>, I>>(base?: I): RestoreVersion {
+    return RestoreVersion.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<RestoreVersion>, I>>(object: I): RestoreVersion {
+    const message = createBaseRestoreVersion();
+    message.id = object.id ?? 0;
+    return message;
+  },
+};
+
+function createBaseItem(): Item {
+  return { id: "", text: undefined, hdr: undefined };
+}
+
+export const Item: MessageFns<Item> = {
+  encode(message: Item, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.id !== "") {
+      writer.uint32(10).string(message.id);
+    }
+    if (message.text !== undefined) {
+      Text.encode(message.text, writer.uint32(18).fork()).join();
+    }
+    if (message.hdr !== undefined) {
+      FileHeader.encode(message.hdr, writer.uint32(26).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Item {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseItem();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.id = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.text = Text.decode(reader, reader.uint32());
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.hdr = FileHeader.decode(reader, reader.uint32());
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Item {
+    return {
+      id: isSet(object.id) ? globalThis.String(object.id) : "",
+      text: isSet(object.text) ? Text.fromJSON(object.text) : undefined,
+      hdr: isSet(object.hdr) ? FileHeader.fromJSON(object.hdr) : undefined,
+    };
+  },
+
+  toJSON(message: Item): unknown {
+    const obj: any = {};
+    if (message.id !== "") {
+      obj.id = message.id;
+    }
+    if (message.text !== undefined) {
+      obj.text = Text.toJSON(message.text);
+    }
+    if (message.hdr !== undefined) {
+      obj.hdr = FileHeader.toJSON(message.hdr);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Item>, I>>(base?: I): Item {
+    return Item.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Item>, I>>(object: I): Item {
+    const message = createBaseItem();
+    message.id = object.id ?? "";
+    message.text = (object.text !== undefined && object.text !== null) ? Text.fromPartial(object.text) : undefined;
+    message.hdr = (object.hdr !== undefined && object.hdr !== null) ? FileHeader.fromPartial(object.hdr) : undefined;
+    return message;
+  },
+};
+
+function createBaseItems(): Items {
+  return { items: [] };
+}
+
+export const Items: MessageFns<Items> = {
+  encode(message: Items, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    for (const v of message.items) {
+      Item.encode(v!, writer.uint32(10).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Items {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseItems();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.items.push(Item.decode(reader, reader.uint32()));
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Items {
+    return { items: globalThis.Array.isArray(object?.items) ? object.items.map((e: any) => Item.fromJSON(e)) : [] };
+  },
+
+  toJSON(message: Items): unknown {
+    const obj: any = {};
+    if (message.items?.length) {
+      obj.items = message.items.map((e) => Item.toJSON(e));
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Items>, I>>(base?: I): Items {
+    return Items.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Items>, I>>(object: I): Items {
+    const message = createBaseItems();
+    message.items = object.items?.map((e) => Item.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseAddItem(): AddItem {
+  return { text: undefined, hdr: undefined };
+}
+
+export const AddItem: MessageFns<AddItem> = {
+  encode(message: AddItem, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.text !== undefined) {
+      Text.encode(message.text, writer.uint32(10).fork()).join();
+    }
+    if (message.hdr !== undefined) {
+      FileHeader.encode(message.hdr, writer.uint32(18).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): AddItem {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseAddItem();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.text = Text.decode(reader, reader.uint32());
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.hdr = FileHeader.decode(reader, reader.uint32());
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): AddItem {
+    return {
+      text: isSet(object.text) ? Text.fromJSON(object.text) : undefined,
+      hdr: isSet(object.hdr) ? FileHeader.fromJSON(object.hdr) : undefined,
+    };
+  },
+
+  toJSON(message: AddItem): unknown {
+    const obj: any = {};
+    if (message.text !== undefined) {
+      obj.text = Text.toJSON(message.text);
+    }
+    if (message.hdr !== undefined) {
+      obj.hdr = FileHeader.toJSON(message.hdr);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<AddItem>, I>>(base?: I): AddItem {
+    return AddItem.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<AddItem>, I>>(object: I): AddItem {
+    const message = createBaseAddItem();
+    message.text = (object.text !== undefined && object.text !== null) ? Text.fromPartial(object.text) : undefined;
+    message.hdr = (object.hdr !== undefined && object.hdr !== null) ? FileHeader.fromPartial(object.hdr) : undefined;
+    return message;
+  },
+};
+
+function createBaseRemoveItem(): RemoveItem {
+  return { id: "" };
+}
+
+export const RemoveItem: MessageFns<RemoveItem> = {
+  encode(message: RemoveItem, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.id !== "") {
+      writer.uint32(10).string(message.id);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): RemoveItem {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseRemoveItem();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.id = reader.string();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): RemoveItem {
+    return { id: isSet(object.id) ? globalThis.String(object.id) : "" };
+  },
+
+  toJSON(message: RemoveItem): unknown {
+    const obj: any = {};
+    if (message.id !== "") {
+      obj.id = message.id;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<RemoveItem>, I>>(base?: I): RemoveItem {
+    return RemoveItem.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<RemoveItem>, I>>(object: I): RemoveItem {
+    const message = createBaseRemoveItem();
+    message.id = object.id ?? "";
+    return message;
+  },
+};
+
+function createBaseMoveItem(): MoveItem {
+  return { id: "", position: 0 };
+}
+
+export const MoveItem: MessageFns<MoveItem> = {
+  encode(message: MoveItem, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.id !== "") {
+      writer.uint32(10).string(message.id);
+    }
+    if (message.position !== 0) {
+      writer.uint32(16).int32(message.position);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): MoveItem {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseMoveItem();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.id = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 16) {
+            break;
+          }
+
+          message.position = reader.int32();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): MoveItem {
+    return {
+      id: isSet(object.id) ? globalThis.String(object.id) : "",
+      position: isSet(object.position) ? globalThis.Number(object.position) : 0,
+    };
+  },
+
+  toJSON(message: MoveItem): unknown {
+    const obj: any = {};
+    if (message.id !== "") {
+      obj.id = message.id;
+    }
+    if (message.position !== 0) {
+      obj.position = Math.round(message.position);
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<MoveItem>, I>>(base?: I): MoveItem {
+    return MoveItem.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<MoveItem>, I>>(object: I): MoveItem {
+    const message = createBaseMoveItem();
+    message.id = object.id ?? "";
+    message.position = object.position ?? 0;
+    return message;
+  },
+};
+
+function createBaseEvent(): Event {
+  return { version: 0, author: "", timestamp: 0, items: [] };
+}
+
+export const Event: MessageFns<Event> = {
+  encode(message: Event, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.version !== 0) {
+      writer.uint32(8).int32(message.version);
+    }
+    if (message.author !== "") {
+      writer.uint32(18).string(message.author);
+    }
+    if (message.timestamp !== 0) {
+      writer.uint32(24).int64(message.timestamp);
+    }
+    for (const v of message.items) {
+      Item.encode(v!, writer.uint32(34).fork()).join();
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Event {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseEvent();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.version = reader.int32();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.author = reader.string();
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.timestamp = longToNumber(reader.int64());
+          continue;
+        }
+        case 4: {
+          if (tag !== 34) {
+            break;
+          }
+
+          message.items.push(Item.decode(reader, reader.uint32()));
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Event {
+    return {
+      version: isSet(object.version) ? globalThis.Number(object.version) : 0,
+      author: isSet(object.author) ? globalThis.String(object.author) : "",
+      timestamp: isSet(object.timestamp) ? globalThis.Number(object.timestamp) : 0,
+      items: globalThis.Array.isArray(object?.items) ? object.items.map((e: any) => Item.fromJSON(e)) : [],
+    };
+  },
+
+  toJSON(message: Event): unknown {
+    const obj: any = {};
+    if (message.version !== 0) {
+      obj.version = Math.round(message.version);
+    }
+    if (message.author !== "") {
+      obj.author = message.author;
+    }
+    if (message.timestamp !== 0) {
+      obj.timestamp = Math.round(message.timestamp);
+    }
+    if (message.items?.length) {
+      obj.items = message.items.map((e) => Item.toJSON(e));
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Event>, I>>(base?: I): Event {
+    return Event.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Event>, I>>(object: I): Event {
+    const message = createBaseEvent();
+    message.version = object.version ?? 0;
+    message.author = object.author ?? "";
+    message.timestamp = object.timestamp ?? 0;
+    message.items = object.items?.map((e) => Item.fromPartial(e)) || [];
+    return message;
+  },
+};
+
+function createBaseHello(): Hello {
+  return { version: 0, capabilities: [], clientId: "" };
+}
+
+export const Hello: MessageFns<Hello> = {
+  encode(message: Hello, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.version !== 0) {
+      writer.uint32(8).int32(message.version);
+    }
+    writer.uint32(18).fork();
+    for (const v of message.capabilities) {
+      writer.int32(v);
+    }
+    writer.join();
+    if (message.clientId !== "") {
+      writer.uint32(26).string(message.clientId);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Hello {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseHello();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.version = reader.int32();
+          continue;
+        }
+        case 2: {
+          if (tag === 16) {
+            message.capabilities.push(reader.int32() as any);
+
+            continue;
+          }
+
+          if (tag === 18) {
+            const end2 = reader.uint32() + reader.pos;
+            while (reader.pos < end2) {
+              message.capabilities.push(reader.int32() as any);
+            }
+
+            continue;
+          }
+
+          break;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.clientId = reader.string();
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Hello {
+    return {
+      version: isSet(object.version) ? globalThis.Number(object.version) : 0,
+      capabilities: globalThis.Array.isArray(object?.capabilities)
+        ? object.capabilities.map((e: any) => capabilityFromJSON(e))
+        : [],
+      clientId: isSet(object.clientId) ? globalThis.String(object.clientId) : "",
+    };
+  },
+
+  toJSON(message: Hello): unknown {
+    const obj: any = {};
+    if (message.version !== 0) {
+      obj.version = Math.round(message.version);
+    }
+    if (message.capabilities?.length) {
+      obj.capabilities = message.capabilities.map((e) => capabilityToJSON(e));
+    }
+    if (message.clientId !== "") {
+      obj.clientId = message.clientId;
+    }
+    return obj;
+  },
+
+  create<I extends Exact<DeepPartial<Hello>, I>>(base?: I): Hello {
+    return Hello.fromPartial(base ?? ({} as any));
+  },
+  fromPartial<I extends Exact<DeepPartial<Hello>, I>>(object: I): Hello {
+    const message = createBaseHello();
+    message.version = object.version ?? 0;
+    message.capabilities = object.capabilities?.map((e) => e) || [];
+    message.clientId = object.clientId ?? "";
+    return message;
+  },
+};
+
+function createBaseWelcome(): Welcome {
+  return {
+    version: 0,
+    capabilities: [],
+    encrypted: false,
+    readOnly: false,
+    burnAfterReading: false,
+    ttl: 0,
+    expires: 0,
+  };
+}
+
+export const Welcome: MessageFns<Welcome> = {
+  encode(message: Welcome, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.version !== 0) {
+      writer.uint32(8).int32(message.version);
+    }
+    writer.uint32(18).fork();
+    for (const v of message.capabilities) {
+      writer.int32(v);
+    }
+    writer.join();
+    if (message.encrypted !== false) {
+      writer.uint32(24).bool(message.encrypted);
+    }
+    if (message.readOnly !== false) {
+      writer.uint32(32).bool(message.readOnly);
+    }
+    if (message.burnAfterReading !== false) {
+      writer.uint32(40).bool(message.burnAfterReading);
+    }
+    if (message.ttl !== 0) {
+      writer.uint32(48).int64(message.ttl);
+    }
+    if (message.expires !== 0) {
+      writer.uint32(56).int64(message.expires);
+    }
+    return writer;
+  },
+
+  decode(input: BinaryReader | Uint8Array, length?: number): Welcome {
+    const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
+    const end = length === undefined ? reader.len : reader.pos + length;
+    const message = createBaseWelcome();
+    while (reader.pos < end) {
+      const tag = reader.uint32();
+      switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 8) {
+            break;
+          }
+
+          message.version = reader.int32();
+          continue;
+        }
+        case 2: {
+          if (tag === 16) {
+            message.capabilities.push(reader.int32() as any);
+
+            continue;
+          }
+
+          if (tag === 18) {
+            const end2 = reader.uint32() + reader.pos;
+            while (reader.pos < end2) {
+              message.capabilities.push(reader.int32() as any);
+            }
+
+            continue;
+          }
+
+          break;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.encrypted = reader.bool();
+          continue;
+        }
+        case 4: {
+          if (tag !== 32) {
+            break;
+          }
+
+          message.readOnly = reader.bool();
+          continue;
+        }
+        case 5: {
+          if (tag !== 40) {
+            break;
+          }
+
+          message.burnAfterReading = reader.bool();
+          continue;
+        }
+        case 6: {
+          if (tag !== 48) {
+            break;
+          }
+
+          message.ttl = longToNumber(reader.int64());
+          continue;
+        }
+        case 7: {
+          if (tag !== 56) {
+            break;
+          }
+
+          message.expires = longToNumber(reader.int64());
+          continue;
+        }
+      }
+      if ((tag & 7) === 4 || tag === 0) {
+        break;
+      }
+      reader.skip(tag & 7);
+    }
+    return message;
+  },
+
+  fromJSON(object: any): Welcome {
+    return {
+      version: isSet(object.version) ? globalThis.Number(object.version) : 0,
+      capabilities: globalThis.Array.isArray(object?.capabilities)
+        ? object.capabilities.map((e: any) => capabilityFromJSON(e))
+        : [],
+      encrypted: isSet(object.encrypted) ? globalThis.Boolean(object.encrypted) : false,
+      readOnly: isSet(object.readOnly) ? globalThis.Boolean(object.readOnly) : false,
+      burnAfterReading: isSet(object.burnAfterReading) ? globalThis.Boolean(object.burnAfterReading) : false,
+      ttl: isSet(object.ttl) ? globalThis.Number(object.ttl) : 0,
+      expires: isSet(object.expires) ? globalThis.Number(object.expires) : 0,
+    };
+  },
+
+  toJSON(message: Welcome): unknown {
+    const obj: any = {};
+    if (message.version !== 0) {
+      obj.version = Math.round(message.version);
+    }
+    if (message.capabilities?.length) {
+      obj.capabilities = message.capabilities.map((e) => capabilityToJSON(e));
+    }
+    if (message.encrypted !== false) {
+      obj.encrypted = message.encrypted;
+    }
+    if (message.readOnly !== false) {
+      obj.readOnly = message.readOnly;
+    }
+    if (message.burnAfterReading !== false) {
+      obj.burnAfterReading = message.burnAfterReading;
+    }
+    if (message.ttl !== 0) {
+      obj.ttl = Math.round(message.ttl);
+    }
+    if (message.expires !== 0) {
+      obj.expires = Math.round(message.expires);
     }
     return obj;
   },
 
-  create<I extends Exact<DeepPartial<FileHeader>, I>>(base?: I): FileHeader {
-    return FileHeader.fromPartial(base ?? ({} as any));
+  create<I extends Exact<DeepPartial<Welcome>, I>>(base?: I): Welcome {
+    return Welcome.fromPartial(base ?? ({} as any));
   },
-  fromPartial<I extends Exact<DeepPartial<FileHeader>, I>>(object: I): FileHeader {
-    const message = createBaseFileHeader();
-    message.filename = object.filename ?? "";
-    message.contentType = object.contentType ?? "";
-    message.numChunks = object.numChunks ?? 0;
+  fromPartial<I extends Exact<DeepPartial<Welcome>, I>>(object: I): Welcome {
+    const message = createBaseWelcome();
+    message.version = object.version ?? 0;
+    message.capabilities = object.capabilities?.map((e) => e) || [];
+    message.encrypted = object.encrypted ?? false;
+    message.readOnly = object.readOnly ?? false;
+    message.burnAfterReading = object.burnAfterReading ?? false;
+    message.ttl = object.ttl ?? 0;
+    message.expires = object.expires ?? 0;
     return message;
   },
 };
 
-function createBaseChunk(): Chunk {
-  return { index: 0, data: new Uint8Array(0) };
+function createBaseError(): Error {
+  return { fatal: false, desc: "", code: 0, busy: undefined };
 }
 
-export const Chunk: MessageFns<Chunk> = {
-  encode(message: Chunk, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
-    if (message.index !== 0) {
-      writer.uint32(8).int32(message.index);
+export const Error: MessageFns<Error> = {
+  encode(message: Error, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.fatal !== false) {
+      writer.uint32(8).bool(message.fatal);
     }
-    if (message.data.length !== 0) {
-      writer.uint32(18).bytes(message.data);
+    if (message.desc !== "") {
+      writer.uint32(18).string(message.desc);
+    }
+    if (message.code !== 0) {
+      writer.uint32(32).int32(message.code);
+    }
+    if (message.busy !== undefined) {
+      Busy.encode(message.busy, writer.uint32(26).fork()).join();
     }
     return writer;
   },
 
-  decode(input: BinaryReader | Uint8Array, length?: number): Chunk {
+  decode(input: BinaryReader | Uint8Array, length?: number): Error {
     const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
     const end = length === undefined ? reader.len : reader.pos + length;
-    const message = createBaseChunk();
+    const message = createBaseError();
     while (reader.pos < end) {
       const tag = reader.uint32();
       switch (tag >>> 3) {
@@ -363,7 +3146,7 @@ export const Chunk: MessageFns<Chunk> = {
             break;
           }
 
-          message.index = reader.int32();
+          message.fatal = reader.bool();
           continue;
         }
         case 2: {
@@ -371,7 +3154,23 @@ export const Chunk: MessageFns<Chunk> = {
             break;
           }
 
-          message.data = reader.bytes();
+          message.desc = reader.string();
+          continue;
+        }
+        case 4: {
+          if (tag !== 32) {
+            break;
+          }
+
+          message.code = reader.int32() as any;
+          continue;
+        }
+        case 3: {
+          if (tag !== 26) {
+            break;
+          }
+
+          message.busy = Busy.decode(reader, reader.uint32());
           continue;
         }
       }
@@ -383,51 +3182,105 @@ export const Chunk: MessageFns<Chunk> = {
     return message;
   },
 
-  fromJSON(object: any): Chunk {
+  fromJSON(object: any): Error {
     return {
-      index: isSet(object.index) ? globalThis.Number(object.index) : 0,
-      data: isSet(object.data) ? bytesFromBase64(object.data) : new Uint8Array(0),
+      fatal: isSet(object.fatal) ? globalThis.Boolean(object.fatal) : false,
+      desc: isSet(object.desc) ? globalThis.String(object.desc) : "",
+      code: isSet(object.code) ? errorCodeFromJSON(object.code) : 0,
+      busy: isSet(object.busy) ? Busy.fromJSON(object.busy) : undefined,
     };
   },
 
-  toJSON(message: Chunk): unknown {
+  toJSON(message: Error): unknown {
     const obj: any = {};
-    if (message.index !== 0) {
-      obj.index = Math.round(message.index);
+    if (message.fatal !== false) {
+      obj.fatal = message.fatal;
     }
-    if (message.data.length !== 0) {
-      obj.data = base64FromBytes(message.data);
+    if (message.desc !== "") {
+      obj.desc = message.desc;
+    }
+    if (message.code !== 0) {
+      obj.code = errorCodeToJSON(message.code);
+    }
+    if (message.busy !== undefined) {
+      obj.busy = Busy.toJSON(message.busy);
     }
     return obj;
   },
 
-  create<I extends Exact<DeepPartial<Chunk>, I>>(base?: I): Chunk {
-    return Chunk.fromPartial(base ?? ({} as any));
+  create<I extends Exact<DeepPartial<Error>, I>>(base?: I): Error {
+    return Error.fromPartial(base ?? ({} as any));
   },
-  fromPartial<I extends Exact<DeepPartial<Chunk>, I>>(object: I): Chunk {
-    const message = createBaseChunk();
-    message.index = object.index ?? 0;
-    message.data = object.data ?? new Uint8Array(0);
+  fromPartial<I extends Exact<DeepPartial<Error>, I>>(object: I): Error {
+    const message = createBaseError();
+    message.fatal = object.fatal ?? false;
+    message.desc = object.desc ?? "";
+    message.code = object.code ?? 0;
+    message.busy = (object.busy !== undefined && object.busy !== null) ? Busy.fromPartial(object.busy) : undefined;
     return message;
   },
 };
 
-function createBaseNextChunk(): NextChunk {
-  return {};
+function createBaseBusy(): Busy {
+  return { uploader: "", filename: "", received: 0, numChunks: 0 };
 }
 
-export const NextChunk: MessageFns<NextChunk> = {
-  encode(_: NextChunk, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+export const Busy: MessageFns<Busy> = {
+  encode(message: Busy, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.uploader !== "") {
+      writer.uint32(10).string(message.uploader);
+    }
+    if (message.filename !== "") {
+      writer.uint32(18).string(message.filename);
+    }
+    if (message.received !== 0) {
+      writer.uint32(24).int32(message.received);
+    }
+    if (message.numChunks !== 0) {
+      writer.uint32(32).int32(message.numChunks);
+    }
     return writer;
   },
 
-  decode(input: BinaryReader | Uint8Array, length?: number): NextChunk {
+  decode(input: BinaryReader | Uint8Array, length?: number): Busy {
     const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
     const end = length === undefined ? reader.len : reader.pos + length;
-    const message = createBaseNextChunk();
+    const message = createBaseBusy();
     while (reader.pos < end) {
       const tag = reader.uint32();
       switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.uploader = reader.string();
+          continue;
+        }
+        case 2: {
+          if (tag !== 18) {
+            break;
+          }
+
+          message.filename = reader.string();
+          continue;
+        }
+        case 3: {
+          if (tag !== 24) {
+            break;
+          }
+
+          message.received = reader.int32();
+          continue;
+        }
+        case 4: {
+          if (tag !== 32) {
+            break;
+          }
+
+          message.numChunks = reader.int32();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -437,40 +3290,72 @@ export const NextChunk: MessageFns<NextChunk> = {
     return message;
   },
 
-  fromJSON(_: any): NextChunk {
-    return {};
+  fromJSON(object: any): Busy {
+    return {
+      uploader: isSet(object.uploader) ? globalThis.String(object.uploader) : "",
+      filename: isSet(object.filename) ? globalThis.String(object.filename) : "",
+      received: isSet(object.received) ? globalThis.Number(object.received) : 0,
+      numChunks: isSet(object.numChunks) ? globalThis.Number(object.numChunks) : 0,
+    };
   },
 
-  toJSON(_: NextChunk): unknown {
+  toJSON(message: Busy): unknown {
     const obj: any = {};
+    if (message.uploader !== "") {
+      obj.uploader = message.uploader;
+    }
+    if (message.filename !== "") {
+      obj.filename = message.filename;
+    }
+    if (message.received !== 0) {
+      obj.received = Math.round(message.received);
+    }
+    if (message.numChunks !== 0) {
+      obj.numChunks = Math.round(message.numChunks);
+    }
     return obj;
   },
 
-  create<I extends Exact<DeepPartial<NextChunk>, I>>(base?: I): NextChunk {
-    return NextChunk.fromPartial(base ?? ({} as any));
+  create<I extends Exact<DeepPartial<Busy>, I>>(base?: I): Busy {
+    return Busy.fromPartial(base ?? ({} as any));
   },
-  fromPartial<I extends Exact<DeepPartial<NextChunk>, I>>(_: I): NextChunk {
-    const message = createBaseNextChunk();
+  fromPartial<I extends Exact<DeepPartial<Busy>, I>>(object: I): Busy {
+    const message = createBaseBusy();
+    message.uploader = object.uploader ?? "";
+    message.filename = object.filename ?? "";
+    message.received = object.received ?? 0;
+    message.numChunks = object.numChunks ?? 0;
     return message;
   },
 };
 
-function createBaseAck(): Ack {
-  return {};
+function createBaseConsumed(): Consumed {
+  return { item: "" };
 }
 
-export const Ack: MessageFns<Ack> = {
-  encode(_: Ack, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+export const Consumed: MessageFns<Consumed> = {
+  encode(message: Consumed, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.item !== "") {
+      writer.uint32(10).string(message.item);
+    }
     return writer;
   },
 
-  decode(input: BinaryReader | Uint8Array, length?: number): Ack {
+  decode(input: BinaryReader | Uint8Array, length?: number): Consumed {
     const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
     const end = length === undefined ? reader.len : reader.pos + length;
-    const message = createBaseAck();
+    const message = createBaseConsumed();
     while (reader.pos < end) {
       const tag = reader.uint32();
       switch (tag >>> 3) {
+        case 1: {
+          if (tag !== 10) {
+            break;
+          }
+
+          message.item = reader.string();
+          continue;
+        }
       }
       if ((tag & 7) === 4 || tag === 0) {
         break;
@@ -480,43 +3365,44 @@ export const Ack: MessageFns<Ack> = {
     return message;
   },
 
-  fromJSON(_: any): Ack {
-    return {};
+  fromJSON(object: any): Consumed {
+    return { item: isSet(object.item) ? globalThis.String(object.item) : "" };
   },
 
-  toJSON(_: Ack): unknown {
+  toJSON(message: Consumed): unknown {
     const obj: any = {};
+    if (message.item !== "") {
+      obj.item = message.item;
+    }
     return obj;
   },
 
-  create<I extends Exact<DeepPartial<Ack>, I>>(base?: I): Ack {
-    return Ack.fromPartial(base ?? ({} as any));
+  create<I extends Exact<DeepPartial<Consumed>, I>>(base?: I): Consumed {
+    return Consumed.fromPartial(base ?? ({} as any));
   },
-  fromPartial<I extends Exact<DeepPartial<Ack>, I>>(_: I): Ack {
-    const message = createBaseAck();
+  fromPartial<I extends Exact<DeepPartial<Consumed>, I>>(object: I): Consumed {
+    const message = createBaseConsumed();
+    message.item = object.item ?? "";
     return message;
   },
 };
 
-function createBaseError(): Error {
-  return { fatal: false, desc: "" };
+function createBaseExpiry(): Expiry {
+  return { timestamp: 0 };
 }
 
-export const Error: MessageFns<Error> = {
-  encode(message: Error, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
-    if (message.fatal !== false) {
-      writer.uint32(8).bool(message.fatal);
-    }
-    if (message.desc !== "") {
-      writer.uint32(18).string(message.desc);
+export const Expiry: MessageFns<Expiry> = {
+  encode(message: Expiry, writer: BinaryWriter = new BinaryWriter()): BinaryWriter {
+    if (message.timestamp !== 0) {
+      writer.uint32(8).int64(message.timestamp);
     }
     return writer;
   },
 
-  decode(input: BinaryReader | Uint8Array, length?: number): Error {
+  decode(input: BinaryReader | Uint8Array, length?: number): Expiry {
     const reader = input instanceof BinaryReader ? input : new BinaryReader(input);
     const end = length === undefined ? reader.len : reader.pos + length;
-    const message = createBaseError();
+    const message = createBaseExpiry();
     while (reader.pos < end) {
       const tag = reader.uint32();
       switch (tag >>> 3) {
@@ -525,15 +3411,7 @@ export const Error: MessageFns<Error> = {
             break;
           }
 
-          message.fatal = reader.bool();
-          continue;
-        }
-        case 2: {
-          if (tag !== 18) {
-            break;
-          }
-
-          message.desc = reader.string();
+          message.timestamp = longToNumber(reader.int64());
           continue;
         }
       }
@@ -545,31 +3423,24 @@ export const Error: MessageFns<Error> = {
     return message;
   },
 
-  fromJSON(object: any): Error {
-    return {
-      fatal: isSet(object.fatal) ? globalThis.Boolean(object.fatal) : false,
-      desc: isSet(object.desc) ? globalThis.String(object.desc) : "",
-    };
+  fromJSON(object: any): Expiry {
+    return { timestamp: isSet(object.timestamp) ? globalThis.Number(object.timestamp) : 0 };
   },
 
-  toJSON(message: Error): unknown {
+  toJSON(message: Expiry): unknown {
     const obj: any = {};
-    if (message.fatal !== false) {
-      obj.fatal = message.fatal;
-    }
-    if (message.desc !== "") {
-      obj.desc = message.desc;
+    if (message.timestamp !== 0) {
+      obj.timestamp = Math.round(message.timestamp);
     }
     return obj;
   },
 
-  create<I extends Exact<DeepPartial<Error>, I>>(base?: I): Error {
-    return Error.fromPartial(base ?? ({} as any));
+  create<I extends Exact<DeepPartial<Expiry>, I>>(base?: I): Expiry {
+    return Expiry.fromPartial(base ?? ({} as any));
   },
-  fromPartial<I extends Exact<DeepPartial<Error>, I>>(object: I): Error {
-    const message = createBaseError();
-    message.fatal = object.fatal ?? false;
-    message.desc = object.desc ?? "";
+  fromPartial<I extends Exact<DeepPartial<Expiry>, I>>(object: I): Expiry {
+    const message = createBaseExpiry();
+    message.timestamp = object.timestamp ?? 0;
     return message;
   },
 };
@@ -611,6 +3482,17 @@ type KeysOfUnion<T> = T extends T ? keyof T : never;
 export type Exact<P, I extends P> = P extends Builtin ? P
   : P & { [K in keyof P]: Exact<P[K], I[K]> } & { [K in Exclude<keyof I, KeysOfUnion<P>>]: never };
 
+function longToNumber(int64: { toString(): string }): number {
+  const num = globalThis.Number(int64.toString());
+  if (num > globalThis.Number.MAX_SAFE_INTEGER) {
+    throw new globalThis.Error("Value is larger than Number.MAX_SAFE_INTEGER");
+  }
+  if (num < globalThis.Number.MIN_SAFE_INTEGER) {
+    throw new globalThis.Error("Value is smaller than Number.MIN_SAFE_INTEGER");
+  }
+  return num;
+}
+
 function isSet(value: any): boolean {
   return value !== null && value !== undefined;
 }
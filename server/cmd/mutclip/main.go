@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"mutclip/pkg/client"
+
+	"github.com/charmbracelet/log"
+)
+
+const usage = `usage: mutclip [-server URL] [-password PASSWORD] <command> [arguments]
+
+commands:
+  new [-encrypted] [-burn] [-ttl TTL] [-expires DURATION]
+                                       create a clip, protected by the password if one is given, and print
+                                       its editor id and then its read-only viewer id
+  push [-id ID] [-add] [-encrypted] [-burn] [-ttl TTL] [-expires DURATION] [-wait] [FILE...]
+                                       push stdin as text, or files, creating a clip unless an id is given,
+                                       and wait until another client has read them if -wait is set
+  pull -id ID [-item ITEM] [-o PATH]   write the top item, or the given one, to stdout or PATH
+  watch -id ID [-all]                  print the top item, or all of them, each time the clip changes,
+                                       and warn when the clip is about to end
+
+The server defaults to $MUTCLIP_SERVER, and the password to $MUTCLIP_PASSWORD. Ids are printed as
+ID:TOKEN, where the token grants editing or only viewing the clip. Ids of encrypted clips are followed
+by #KEY, and the key never leaves the client. Created clips end once nobody has been connected to them
+for their TTL, which defaults to the one of the server, or DURATION after their creation if -expires
+is given.
+`
+
+func main() {
+	server := os.Getenv("MUTCLIP_SERVER")
+	if server == "" {
+		server = "http://localhost:5000"
+	}
+
+	var opts client.Options
+	flag.StringVar(&server, "server", server, "address of the mutclip server")
+	flag.StringVar(&opts.Password, "password", os.Getenv("MUTCLIP_PASSWORD"), "password of the clip")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+
+	flags := flag.NewFlagSet(cmd, flag.ExitOnError)
+	id := flags.String("id", "", "clip id")
+
+	ctx := context.Background()
+
+	var err error
+	switch cmd {
+
+	case "new":
+		encrypted := flags.Bool("encrypted", false, "seal the contents of the clip with a key only known to its clients")
+		flags.BoolVar(&opts.BurnAfterReading, "burn", false, "end the clip once another client has read its contents")
+		flags.DurationVar(&opts.TTL, "ttl", 0, "how long the created clip lives on once nobody is connected")
+		expires := flags.Duration("expires", 0, "time after its creation at which the created clip ends")
+		flags.Parse(args)
+
+		opts.Expires = expiry(*expires)
+
+		var editor, viewer string
+		editor, viewer, err = newClip(ctx, server, opts, *encrypted)
+		if err == nil {
+			fmt.Println(editor)
+			fmt.Println(viewer)
+		}
+
+	case "push":
+		add := flags.Bool("add", false, "push on top of the items of the clip instead of replacing them")
+		encrypted := flags.Bool("encrypted", false, "seal the contents of the created clip with a key only known to its clients")
+		flags.BoolVar(&opts.BurnAfterReading, "burn", false, "end the created clip once another client has read its contents")
+		wait := flags.Bool("wait", false, "wait until another client has read the contents of a burn after reading clip")
+		flags.DurationVar(&opts.TTL, "ttl", 0, "how long the created clip lives on once nobody is connected")
+		expires := flags.Duration("expires", 0, "time after its creation at which the created clip ends")
+		flags.Parse(args)
+
+		opts.Expires = expiry(*expires)
+
+		err = runPush(ctx, server, opts, *id, flags.Args(), *add, *encrypted, *wait)
+
+	case "pull":
+		item := flags.String("item", "", "id of the item to pull, the top one by default")
+		out := flags.String("o", "", `output path, "-" for stdout`)
+		flags.Parse(args)
+
+		err = runPull(ctx, server, opts, *id, *item, *out)
+
+	case "watch":
+		all := flags.Bool("all", false, "print all the items")
+		flags.Parse(args)
+
+		err = runWatch(ctx, server, opts, *id, *all)
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// expiry returns the expiry of a clip created now that ends after d, zero for never.
+func expiry(d time.Duration) time.Time {
+	if d == 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(d)
+}
+
+// newClip creates a clip and returns its ids for editors and viewers.
+func newClip(ctx context.Context, server string, opts client.Options, encrypted bool) (string, string, error) {
+	if encrypted {
+		key, err := client.NewKey()
+		if err != nil {
+			return "", "", err
+		}
+
+		opts.Key = key
+	}
+
+	clip, err := client.NewClip(ctx, server, opts)
+	if err != nil {
+		return "", "", err
+	}
+
+	return formatId(clip.Id, clip.EditorToken, opts.Key), formatId(clip.Id, clip.ViewerToken, opts.Key), nil
+}
+
+// formatId joins the token and the key of an encrypted clip to its id.
+func formatId(id string, token string, key []byte) string {
+	if token != "" {
+		id += ":" + token
+	}
+
+	if key != nil {
+		id += "#" + client.EncodeKey(key)
+	}
+
+	return id
+}
+
+// parseId splits the token and the key of an encrypted clip off its id.
+func parseId(id string, opts *client.Options) (string, error) {
+	id, key, encrypted := strings.Cut(id, "#")
+	id, opts.Token, _ = strings.Cut(id, ":")
+
+	if !encrypted {
+		return id, nil
+	}
+
+	var err error
+	opts.Key, err = client.DecodeKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func runPush(ctx context.Context, server string, opts client.Options, id string, files []string, add bool, encrypted bool, wait bool) error {
+	if id == "" {
+		var err error
+		id, _, err = newClip(ctx, server, opts, encrypted)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(id)
+	}
+
+	id, err := parseId(id, &opts)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.Dial(ctx, server, id, opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if len(files) == 0 {
+		err = pushText(ctx, c, os.Stdin, add)
+	}
+
+	for i, file := range files {
+		err = pushFile(ctx, c, file, add || i > 0)
+		if err != nil {
+			return fmt.Errorf("%v: %w", file, err)
+		}
+	}
+
+	if err != nil || !wait {
+		return err
+	}
+
+	return waitConsumed(c)
+}
+
+// waitConsumed waits until the client stops, which it does once the contents it pushed to a burn
+// after reading clip were read.
+func waitConsumed(c *client.Client) error {
+	for range c.Updates {
+	}
+
+	if !c.Consumed() {
+		return c.Err()
+	}
+
+	log.Info("clip was read")
+
+	return nil
+}
+
+func runPull(ctx context.Context, server string, opts client.Options, id string, itemId string, out string) error {
+	if id == "" {
+		return fmt.Errorf("pull: missing clip id")
+	}
+
+	id, err := parseId(id, &opts)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.Dial(ctx, server, id, opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	item, err := findItem(c.Items(), itemId)
+	if err != nil {
+		return err
+	}
+
+	return pull(ctx, c, item, out)
+}
+
+func runWatch(ctx context.Context, server string, opts client.Options, id string, all bool) error {
+	if id == "" {
+		return fmt.Errorf("watch: missing clip id")
+	}
+
+	id, err := parseId(id, &opts)
+	if err != nil {
+		return err
+	}
+
+	c, err := client.Dial(ctx, server, id, opts)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return watch(c, all)
+}
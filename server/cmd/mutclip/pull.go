@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mutclip/pkg/client"
+)
+
+var (
+	ErrEmpty  = errors.New("clip is empty")
+	ErrNoItem = errors.New("no such item")
+)
+
+// Modes given to extracted files and directories whose mode the manifest leaves out.
+const (
+	DefaultFileMode os.FileMode = 0o644
+	DefaultDirMode  os.FileMode = 0o755
+)
+
+// findItem returns the item with the given id, or the top one if id is empty.
+func findItem(items []client.Item, id string) (client.Item, error) {
+	if id == "" {
+		top, ok := client.Top(items)
+		if !ok {
+			return client.Item{}, ErrEmpty
+		}
+
+		return top, nil
+	}
+
+	item, ok := client.Find(items, id)
+	if !ok {
+		return client.Item{}, ErrNoItem
+	}
+
+	return item, nil
+}
+
+// pull writes an item to out, which is stdout for "-" and defaults to the name of the file for files,
+// which must then name a file in the current directory.
+func pull(ctx context.Context, c *client.Client, item client.Item, out string) error {
+	if item.Err != nil {
+		return item.Err
+	}
+
+	if item.File == nil {
+		w := io.Writer(os.Stdout)
+		if out != "" && out != "-" {
+			f, err := os.Create(out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			w = f
+		}
+
+		_, err := io.WriteString(w, item.Text)
+		return err
+	}
+
+	if out == "" {
+		out = filepath.Base(item.File.Name)
+		if !localName(out) {
+			return fmt.Errorf("refusing to write %q, give a path with -o", out)
+		}
+	}
+
+	if len(item.File.Entries) != 0 {
+		return pullTree(ctx, c, item, out)
+	}
+
+	w := io.Writer(os.Stdout)
+	if out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	return c.Download(ctx, item, "", w)
+}
+
+// localName tells whether name is a single element naming a file in the current directory.
+func localName(name string) bool {
+	return name != "." && filepath.IsLocal(name) && !strings.ContainsAny(name, `/\`)
+}
+
+// pullTree extracts the files of a tree into dir, fetching them one by one. Paths which are absolute,
+// or leave dir once cleaned, are refused, and only the permission bits of the modes are kept.
+func pullTree(ctx context.Context, c *client.Client, item client.Item, dir string) error {
+	for _, entry := range item.File.Entries {
+		if strings.Contains(entry.Path, "\\") || !filepath.IsLocal(filepath.FromSlash(entry.Path)) {
+			return fmt.Errorf("refusing to extract %v", entry.Path)
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(entry.Path))
+
+		mode := entry.Mode & os.ModePerm
+
+		if entry.Dir {
+			if mode == 0 {
+				mode = DefaultDirMode
+			}
+
+			err := os.MkdirAll(path, mode|0o700)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if mode == 0 {
+			mode = DefaultFileMode
+		}
+
+		err := os.MkdirAll(filepath.Dir(path), DefaultDirMode)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+		if err != nil {
+			return err
+		}
+
+		err = c.Download(ctx, item, entry.Path, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
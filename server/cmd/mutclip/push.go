@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"mutclip/pkg/client"
+)
+
+const MaxTextSize = 4 << 20
+
+var ErrNotText = errors.New("stdin is not UTF-8 text, push it as a file instead")
+
+func pushText(ctx context.Context, c *client.Client, r io.Reader, add bool) error {
+	buf, err := io.ReadAll(io.LimitReader(r, MaxTextSize+1))
+	if err != nil {
+		return err
+	}
+
+	if len(buf) > MaxTextSize || !utf8.Valid(buf) {
+		return ErrNotText
+	}
+
+	return c.PushText(ctx, string(buf), add)
+}
+
+func pushFile(ctx context.Context, c *client.Client, path string, add bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return err
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	upload := client.Upload{
+		Name:        filepath.Base(path),
+		ContentType: contentType,
+		Size:        stat.Size(),
+		Digest:      hash.Sum(nil),
+		Add:         add,
+	}
+
+	return c.PushFile(ctx, upload, f)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"mutclip/pkg/client"
+
+	"github.com/charmbracelet/log"
+)
+
+func printItem(item client.Item) {
+	if item.Err != nil {
+		fmt.Printf("[item %v: %v]\n", item.Id, item.Err)
+		return
+	}
+
+	if item.File == nil {
+		data := item.Text
+		if !strings.HasSuffix(data, "\n") {
+			data += "\n"
+		}
+
+		fmt.Print(data)
+		return
+	}
+
+	fmt.Printf("[file %v: %v, %v chunks]\n", item.Id, item.File.Name, item.File.NumChunks)
+}
+
+func sameItems(a []client.Item, b []client.Item) bool {
+	return slices.EqualFunc(a, b, func(x client.Item, y client.Item) bool { return x.Id == y.Id })
+}
+
+// watch prints the top item each time the items of the clip change, or the whole listing if all is set.
+// The listings sent after reconnecting are only printed if something changed meanwhile. Warnings that
+// the clip is about to end go to stderr.
+func watch(c *client.Client, all bool) error {
+	var last []client.Item
+	for {
+		select {
+
+		case items, ok := <-c.Updates:
+			if !ok {
+				return c.Err()
+			}
+
+			if last != nil && sameItems(items, last) {
+				continue
+			}
+			last = items
+
+			if all {
+				for _, item := range items {
+					printItem(item)
+				}
+			} else if top, ok := client.Top(items); ok {
+				printItem(top)
+			}
+
+		case end, ok := <-c.Expiring:
+			if !ok {
+				return c.Err()
+			}
+
+			if end.IsZero() {
+				log.Info("clip no longer ends soon")
+				continue
+			}
+
+			log.Warnf("clip ends in %v", time.Until(end).Round(time.Second))
+
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"mutclip/pkg/clipservice"
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	defaultFilename = "upload"
+
+	KeepAliveInterval = time.Second * 30
+
+	roleKey = "role"
+)
+
+// bodyError is an error in the body of a request.
+type bodyError struct {
+	error
+}
+
+// status maps an error reported by the clipboard service to an HTTP status.
+func status(err error) int {
+	if errors.Is(err, clipservice.ErrInvalidClipId) {
+		return 404
+	}
+
+	var be bodyError
+	if errors.As(err, &be) {
+		return 400
+	}
+
+	switch net.Code(err) {
+
+	case pb.ErrorCode_ERROR_CODE_INVALID_ITEM, pb.ErrorCode_ERROR_CODE_INVALID_PATH:
+		return 404
+
+	case pb.ErrorCode_ERROR_CODE_BUSY:
+		return 409
+
+	case pb.ErrorCode_ERROR_CODE_TOO_LARGE:
+		return 413
+
+	case pb.ErrorCode_ERROR_CODE_UNAUTHORIZED, pb.ErrorCode_ERROR_CODE_ENCRYPTION_REQUIRED,
+		pb.ErrorCode_ERROR_CODE_BURN_AFTER_READING:
+		return 403
+
+	case pb.ErrorCode_ERROR_CODE_RATE_LIMITED:
+		return 429
+
+	case pb.ErrorCode_ERROR_CODE_UNSPECIFIED, pb.ErrorCode_ERROR_CODE_INTERNAL:
+		return 500
+
+	default:
+		return 400
+
+	}
+}
+
+// password returns the password sent with a request, as the password of basic authentication, or as
+// the password query parameter for websockets and event sources, which cannot set headers.
+func password(c *gin.Context) string {
+	if _, p, ok := c.Request.BasicAuth(); ok {
+		return p
+	}
+
+	return c.Query("password")
+}
+
+// token returns the token sent with a request, in the X-Clip-Token header or as the token query parameter.
+func token(c *gin.Context) string {
+	if t := c.GetHeader("X-Clip-Token"); t != "" {
+		return t
+	}
+
+	return c.Query("token")
+}
+
+// role returns the role granted to a request by authorize.
+func role(c *gin.Context) clipservice.Role {
+	r, ok := c.MustGet(roleKey).(clipservice.Role)
+	if !ok {
+		panic("impossible")
+	}
+
+	return r
+}
+
+// authorize rejects requests for clipboards which do not exist, whose password is missing or wrong,
+// or whose token is invalid. The role granted by the token is kept in the context.
+func authorize(s *clipservice.ClipboardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r, err := s.Authorize(c.Param("id"), c.ClientIP(), password(c), token(c))
+		if err == nil {
+			c.Set(roleKey, r)
+			return
+		}
+
+		code := status(err)
+		if errors.Is(err, clipservice.ErrPasswordRequired) || errors.Is(err, clipservice.ErrWrongPassword) {
+			c.Header("WWW-Authenticate", `Basic realm="mutclip"`)
+			code = 401
+		}
+
+		c.String(code, err.Error())
+		c.Abort()
+	}
+}
+
+// lifetime returns the TTL and the expiry asked for a new clipboard, as a duration in the ttl query
+// parameter and an RFC 3339 time in the expires one.
+func lifetime(c *gin.Context) (time.Duration, time.Time, error) {
+	var ttl time.Duration
+	var expires time.Time
+	var err error
+
+	if v := c.Query("ttl"); v != "" {
+		ttl, err = time.ParseDuration(v)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+	}
+
+	if v := c.Query("expires"); v != "" {
+		expires, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+	}
+
+	return ttl, expires, nil
+}
+
+// check handles GET /check/:id, which tells how long the clipboard lives and who is connected to it.
+func check(s *clipservice.ClipboardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lifetime, err := s.Lifetime(c.Param("id"))
+		if err != nil {
+			c.String(status(err), err.Error())
+			return
+		}
+
+		m := gin.H{
+			"ttl":     lifetime.TTL.Seconds(),
+			"present": lifetime.Present,
+		}
+
+		if !lifetime.Expires.IsZero() {
+			m["expires"] = lifetime.Expires.Format(time.RFC3339)
+		}
+
+		// clipboards only count down once nobody is connected
+		if !lifetime.End.IsZero() {
+			m["end"] = lifetime.End.Format(time.RFC3339)
+		}
+
+		c.JSON(200, m)
+	}
+}
+
+// spool copies r to a temporary file, for bodies whose size is not known in advance.
+func spool(r io.Reader) (*os.File, int64, error) {
+	f, err := os.CreateTemp("", "mutclip-put-")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	os.Remove(f.Name())
+
+	size, err := io.Copy(f, r)
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+
+	return f, size, nil
+}
+
+// putFile uploads a body of the given size, which is spooled first if it is not known.
+func putFile(put *clipservice.Put, filename string, contentType string, size int64, r io.Reader, add bool) error {
+	if size < 0 {
+		f, n, err := spool(r)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r, size = f, n
+	}
+
+	if filename == "" {
+		filename = defaultFilename
+	}
+
+	return put.File(filename, contentType, size, r, add)
+}
+
+// putClip handles PUT and POST /clip/:id. Multipart forms send their first part, as a file if it has
+// a filename and as text otherwise. Other bodies are text if their type says so, or if they have
+// no type, no filename and hold a small enough UTF-8 text; anything else is a file named after
+// the filename query parameter or the last element of the rest of the path.
+func putClip(s *clipservice.ClipboardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		_, add := c.GetQuery("add")
+
+		filename := c.Query("filename")
+		if p := strings.TrimPrefix(c.Param("filename"), "/"); filename == "" && p != "" {
+			filename = path.Base(p)
+		}
+
+		put, err := s.Put(id, c.Request.Context(), role(c))
+		if err != nil {
+			log.Error(err)
+			c.String(status(err), err.Error())
+			return
+		}
+		defer put.Close()
+
+		err = func() error {
+			mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+
+			switch {
+
+			case mediaType == "multipart/form-data":
+				reader, err := c.Request.MultipartReader()
+				if err != nil {
+					return bodyError{err}
+				}
+
+				part, err := reader.NextPart()
+				if err != nil {
+					return bodyError{err}
+				}
+				defer part.Close()
+
+				if part.FileName() == "" {
+					data, err := io.ReadAll(io.LimitReader(part, clipservice.MaxTextSize+1))
+					if err != nil {
+						return bodyError{err}
+					}
+
+					return put.Text(string(data), add)
+				}
+
+				return putFile(put, part.FileName(), part.Header.Get("Content-Type"), -1, part, add)
+
+			case strings.HasPrefix(mediaType, "text/") && filename == "":
+				data, err := io.ReadAll(io.LimitReader(c.Request.Body, clipservice.MaxTextSize+1))
+				if err != nil {
+					return bodyError{err}
+				}
+
+				return put.Text(string(data), add)
+
+			case mediaType == "" && filename == "":
+				head, err := io.ReadAll(io.LimitReader(c.Request.Body, clipservice.MaxTextSize+1))
+				if err != nil {
+					return bodyError{err}
+				}
+
+				if len(head) <= clipservice.MaxTextSize && utf8.Valid(head) {
+					return put.Text(string(head), add)
+				}
+
+				body := io.MultiReader(bytes.NewReader(head), c.Request.Body)
+				return putFile(put, "", "application/octet-stream", c.Request.ContentLength, body, add)
+
+			default:
+				if mediaType == "" {
+					mediaType = "application/octet-stream"
+				}
+
+				return putFile(put, filename, mediaType, c.Request.ContentLength, c.Request.Body, add)
+
+			}
+		}()
+		if err != nil {
+			log.Error(err)
+			c.String(status(err), err.Error())
+			return
+		}
+
+		c.Status(204)
+	}
+}
+
+// getClip handles GET /clip/:id and /clip/:id/raw, which serve the top item or the one named by
+// the item query parameter. Files are sent as attachments, unless they are requested raw.
+func getClip(s *clipservice.ClipboardService, raw bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+
+		download, err := s.Download(id, c.Query("item"), c.Query("path"))
+		if err != nil {
+			log.Error(err)
+			c.String(status(err), err.Error())
+			return
+		}
+		defer download.Close()
+
+		c.Header("Content-Type", download.ContentType)
+
+		if download.Filename != "" {
+			disposition := "attachment"
+			if raw {
+				disposition = "inline"
+			}
+
+			c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": download.Filename}))
+		}
+
+		http.ServeContent(c.Writer, c.Request, download.Filename, download.Modified, download)
+	}
+}
+
+// events handles GET /events/:id, streaming the changes of the clipboard as server-sent events
+// holding the JSON form of pb.Event.
+func events(s *clipservice.ClipboardService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+
+		sub, err := s.Subscribe(id, c.Request.Context())
+		if err != nil {
+			log.Error(err)
+			c.String(status(err), err.Error())
+			return
+		}
+		defer sub.Cancel()
+
+		c.Header("Cache-Control", "no-cache")
+		c.Header("X-Accel-Buffering", "no")
+
+		keepAlive := time.NewTicker(KeepAliveInterval)
+		defer keepAlive.Stop()
+
+		c.Stream(func(w io.Writer) bool {
+			select {
+
+			case m := <-sub.Out:
+				event := m.GetEvent()
+				if event == nil {
+					return true
+				}
+
+				buf, err := protojson.Marshal(event)
+				if err != nil {
+					log.Error(err)
+					return false
+				}
+
+				c.SSEvent("change", string(buf))
+				return true
+
+			case <-keepAlive.C:
+				_, err := io.WriteString(w, ": keep-alive\n\n")
+				return err == nil
+
+			case <-sub.Done():
+				return false
+
+			}
+		})
+	}
+}
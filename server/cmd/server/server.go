@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,7 +19,14 @@ import (
 	"github.com/gorilla/websocket"
 )
 
-const ConnDeadline = time.Minute
+const (
+	ConnDeadline  = time.Minute
+	FlushDeadline = time.Second
+
+	// MaxMessageSize bounds the websocket messages read from clients, which gorilla buffers whole:
+	// a chunk of MaxChunkSize along with the other fields of the message, or a header and its manifest.
+	MaxMessageSize = clipservice.MaxChunkSize + 1<<20
+)
 
 func main() {
 	gin.DefaultWriter = io.Discard
@@ -25,7 +34,63 @@ func main() {
 
 	r := gin.Default()
 
-	s := clipservice.NewService()
+	// addresses of clients are taken from X-Forwarded-For only when it was set by these proxies, so
+	// that clients cannot dodge the throttling of password attempts
+	err := r.SetTrustedProxies(strings.Fields(os.Getenv("TRUSTED_PROXIES")))
+	if err != nil {
+		log.Fatalf("invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	var store clipservice.ContentStore
+	switch os.Getenv("STORE") {
+
+	case "", "memory":
+		store = clipservice.NewMemoryStore()
+
+	case "fs":
+		fsStore, err := clipservice.NewFileStore(os.Getenv("STORE_DIR"))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		store = fsStore
+
+	default:
+		log.Fatalf("unknown store %v", os.Getenv("STORE"))
+
+	}
+
+	policy := clipservice.DefaultPolicy
+	for env, bound := range map[string]*time.Duration{
+		"DEFAULT_TTL":    &policy.DefaultTTL,
+		"MIN_TTL":        &policy.MinTTL,
+		"MAX_TTL":        &policy.MaxTTL,
+		"MAX_LIFETIME":   &policy.MaxLifetime,
+		"HELLO_DEADLINE": &policy.HelloDeadline,
+	} {
+		v := os.Getenv(env)
+		if v == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid %v: %v", env, err)
+		}
+
+		*bound = d
+	}
+
+	s := clipservice.NewService(store, policy)
+
+	ids, err := s.Restore(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, id := range ids {
+		go s.Start(id)
+	}
 
 	origins := make(map[string]struct{})
 	for _, o := range strings.Split(os.Getenv("ORIGINS"), " ") {
@@ -49,19 +114,76 @@ func main() {
 	}
 
 	r.GET("/newclip", func(c *gin.Context) {
-		id := s.Generate(c)
+		_, encrypted := c.GetQuery("encrypted")
+		_, burn := c.GetQuery("burn")
+
+		ttl, expires, err := lifetime(c)
+		if err != nil {
+			c.String(400, err.Error())
+			return
+		}
+
+		id, tokens, err := s.Generate(c, clipservice.Options{
+			Password:         password(c),
+			Encrypted:        encrypted,
+			BurnAfterReading: burn,
+			TTL:              ttl,
+			Expires:          expires,
+		})
+		if err != nil {
+			log.Error(err)
+			c.String(status(err), err.Error())
+			return
+		}
 
 		go s.Start(id)
 
+		c.Header("X-Editor-Token", tokens.Editor)
+		c.Header("X-Viewer-Token", tokens.Viewer)
+
 		c.String(200, id)
 	})
 
-	r.GET("/check/:id", func(c *gin.Context) {
+	r.GET("/check/:id", authorize(s), check(s))
+
+	r.GET("/clip/:id", authorize(s), getClip(s, false))
+	r.GET("/clip/:id/raw", authorize(s), getClip(s, true))
+	r.PUT("/clip/:id", authorize(s), putClip(s))
+	r.POST("/clip/:id", authorize(s), putClip(s))
+	r.PUT("/clip/:id/*filename", authorize(s), putClip(s))
+	r.POST("/clip/:id/*filename", authorize(s), putClip(s))
+
+	r.GET("/events/:id", authorize(s), events(s))
+
+	r.GET("/archive/:id", authorize(s), func(c *gin.Context) {
 		id := c.Param("id")
-		if s.Check(id) {
-			c.Status(200)
-		} else {
-			c.Status(404)
+
+		format, err := clipservice.ParseArchiveFormat(c.Query("format"))
+		if err != nil {
+			c.String(400, err.Error())
+			return
+		}
+
+		archive, err := s.Archive(id)
+		if err != nil {
+			log.Error(err)
+			c.String(status(err), err.Error())
+			return
+		}
+		defer archive.Close()
+
+		contentType := "application/zip"
+		if format == clipservice.ArchiveTarGz {
+			contentType = "application/gzip"
+		}
+
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%v.%v"`, id, format))
+		c.Status(200)
+
+		err = archive.Write(c.Writer, format)
+		if err != nil {
+			log.Error(err)
 		}
 	})
 
@@ -75,14 +197,44 @@ func main() {
 			return
 		}
 
-		client, err := s.Connect(id, c.Request.Context())
+		role, err := s.Authorize(id, c.ClientIP(), password(c), token(c))
+		if err != nil {
+			log.Error(err)
+
+			// clients are free to try again once they are no longer throttled
+			m := net.Fatal(err)
+			if errors.Is(err, clipservice.ErrRateLimited) {
+				m = net.Err(err)
+			}
+
+			conn.WriteMessage(websocket.BinaryMessage, net.Out(m))
+			conn.Close()
+			return
+		}
+
+		client, err := s.Connect(id, c.Request.Context(), role)
 		if err != nil {
 			log.Error(err)
 			conn.WriteMessage(websocket.BinaryMessage, net.Out(net.Fatal(err)))
 			return
 		}
 
+		conn.SetReadLimit(MaxMessageSize)
+
 		timer := time.NewTimer(ConnDeadline)
+
+		// clients ping idle connections to keep them open
+		conn.SetPingHandler(func(data string) error {
+			timer.Reset(ConnDeadline)
+
+			err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+			if errors.Is(err, websocket.ErrCloseSent) {
+				return nil
+			}
+
+			return err
+		})
+
 		go func() {
 			select {
 
@@ -127,7 +279,7 @@ func main() {
 					m, err := net.In(client.Cid, buf)
 					if err != nil {
 						log.Errorf("unable to parse protobuf message: %v", err)
-						client.Out <- net.Err(fmt.Errorf("unexpected message"))
+						client.Out <- net.Err(net.ErrUnexpected)
 						continue
 					}
 
@@ -138,13 +290,15 @@ func main() {
 
 				default:
 					log.Errorf("unexpected message of type %v: %v", typ, buf)
-					client.Out <- net.Err(fmt.Errorf("unexpected message"))
+					client.Out <- net.Err(net.ErrUnexpected)
 
 				}
 			}
 		}()
 
+		flushed := make(chan struct{})
 		go func() {
+			defer close(flushed)
 			defer client.Cancel()
 
 			for m := range client.Out {
@@ -167,6 +321,11 @@ func main() {
 
 		<-client.Done()
 
+		select {
+		case <-flushed:
+		case <-time.After(FlushDeadline):
+		}
+
 		err = conn.Close()
 		if err != nil {
 			log.Error(err)
@@ -174,7 +333,7 @@ func main() {
 	})
 
 	log.Infof("Server started on port 5000")
-	err := r.Run(":5000")
+	err = r.Run(":5000")
 	if err != nil {
 		log.Error(err)
 	}
@@ -0,0 +1,643 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	ProtocolVersion = 1
+	ChunkSize       = 1 << 20
+	Window          = 8
+
+	// KeepAliveInterval is how often a client pings the server, which drops silent connections.
+	KeepAliveInterval = time.Second * 30
+
+	MinBackoff = time.Second
+	MaxBackoff = time.Second * 30
+)
+
+var (
+	ErrClosed       = errors.New("client closed")
+	ErrDisconnected = errors.New("connection lost")
+	ErrNoItems      = errors.New("server does not support items")
+)
+
+// capabilities are asked for during the handshake. Items make the server send listings instead of
+// pushing files to the client, so that files are only transferred when they are downloaded.
+var capabilities = []pb.Capability{
+	pb.Capability_CAPABILITY_WINDOWING,
+	pb.Capability_CAPABILITY_CHECKSUMS,
+	pb.Capability_CAPABILITY_ITEMS,
+	pb.Capability_CAPABILITY_ENCRYPTION,
+	pb.Capability_CAPABILITY_EXPIRY,
+}
+
+// Options are given when creating or dialing a clip.
+type Options struct {
+	Password string // password of the clip, empty for none
+	Token    string // token granting a role on the clip, empty for clips without tokens
+
+	// BurnAfterReading makes a new clip end once a client other than the uploader has received its contents.
+	BurnAfterReading bool
+
+	TTL     time.Duration // how long a new clip lives on once nobody is connected, 0 for the default of the server
+	Expires time.Time     // time at which a new clip ends, zero for never
+	Key     []byte        // key of an encrypted clip, which is never sent to the server
+}
+
+// fatalError is an error after which reconnecting is pointless.
+type fatalError struct {
+	error
+}
+
+func (e fatalError) Unwrap() error {
+	return e.error
+}
+
+// Client is a connection to a clip, which is reestablished when it drops. Transfers run one at a time,
+// and fail with ErrDisconnected when the connection drops in the middle of them.
+type Client struct {
+	server   string
+	id       string
+	opts     Options
+	clientId string // sent in every hello, so that the client stays the uploader of its items when it reconnects
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// Updates receives the items of the clip on every change and after reconnecting. Only the latest
+	// listing is kept for a receiver that falls behind. Updates is closed once the client stops.
+	Updates <-chan []Item
+	updates chan []Item
+
+	// Expiring receives the time the clip ends when it draws near, and the zero time once it no longer
+	// does. Like Updates, only the latest time is kept and Expiring is closed once the client stops.
+	Expiring <-chan time.Time
+	expiring chan time.Time
+
+	mu       sync.Mutex // guards conn, up, items, readOnly, consumed and err
+	conn     *conn      // nil while reconnecting
+	up       chan struct{}
+	items    []Item
+	readOnly bool
+	consumed bool
+	err      error
+
+	op sync.Mutex       // held by the running transfer
+	in chan *pb.Message // messages for the running transfer
+}
+
+type conn struct {
+	ws       *websocket.Conn
+	mu       sync.Mutex    // serializes writes
+	lost     chan struct{} // closed when the connection drops
+	readOnly bool
+}
+
+// Clip is a newly created clip along with the tokens granting its roles. Whoever holds the viewer token
+// may read the clip but not change it.
+type Clip struct {
+	Id          string
+	EditorToken string
+	ViewerToken string
+}
+
+// NewClip creates a clip, protected by opts.Password if it is set and encrypted if opts.Key is.
+func NewClip(ctx context.Context, server string, opts Options) (Clip, error) {
+	query := url.Values{}
+	if opts.Key != nil {
+		query.Set("encrypted", "")
+	}
+	if opts.BurnAfterReading {
+		query.Set("burn", "")
+	}
+	if opts.TTL != 0 {
+		query.Set("ttl", opts.TTL.String())
+	}
+	if !opts.Expires.IsZero() {
+		query.Set("expires", opts.Expires.Format(time.RFC3339))
+	}
+
+	u := strings.TrimSuffix(server, "/") + "/newclip"
+	if len(query) != 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return Clip{}, err
+	}
+
+	if opts.Password != "" {
+		req.SetBasicAuth("", opts.Password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Clip{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return Clip{}, fmt.Errorf("unable to create clip: %v", resp.Status)
+	}
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Clip{}, err
+	}
+
+	return Clip{
+		Id:          strings.TrimSpace(string(buf)),
+		EditorToken: resp.Header.Get("X-Editor-Token"),
+		ViewerToken: resp.Header.Get("X-Viewer-Token"),
+	}, nil
+}
+
+// Dial connects to the clip id on server, which is a http or https URL. The client stops when ctx is done
+// or Close is called.
+func Dial(ctx context.Context, server string, id string, opts Options) (*Client, error) {
+	if opts.Key != nil && len(opts.Key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	c := &Client{
+		server:   server,
+		id:       id,
+		opts:     opts,
+		clientId: uuid.NewString(),
+		done:     make(chan struct{}),
+		updates:  make(chan []Item, 1),
+		expiring: make(chan time.Time, 1),
+		up:       make(chan struct{}),
+		in:       make(chan *pb.Message, 64),
+	}
+	c.Updates = c.updates
+	c.Expiring = c.expiring
+	c.ctx, c.cancel = context.WithCancel(ctx)
+
+	cn, items, err := c.connect()
+	if err != nil {
+		c.cancel()
+		return nil, err
+	}
+
+	c.attach(cn, items)
+
+	go c.run(cn)
+	go c.keepAlive()
+
+	return c, nil
+}
+
+func (c *Client) Id() string {
+	return c.id
+}
+
+// Items returns the latest listing of the items of the clip.
+func (c *Client) Items() []Item {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.items
+}
+
+// ReadOnly tells whether the client connected as a viewer, which may not change the clip.
+func (c *Client) ReadOnly() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.readOnly
+}
+
+// Consumed tells whether the client stopped because the contents it uploaded to a burn after reading
+// clip were consumed, in which case Err is nil.
+func (c *Client) Consumed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.consumed
+}
+
+// Err returns the reason the client stopped, once Updates is closed.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+func (c *Client) Close() error {
+	c.cancel()
+
+	c.mu.Lock()
+	if c.conn != nil {
+		c.conn.ws.Close()
+	}
+	c.mu.Unlock()
+
+	<-c.done
+	return nil
+}
+
+func (c *Client) url() (string, string, error) {
+	u, err := url.Parse(c.server)
+	if err != nil {
+		return "", "", err
+	}
+
+	origin := u.Scheme + "://" + u.Host
+
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/ws/" + c.id
+
+	return u.String(), origin, nil
+}
+
+// connect dials the clip and says hello, returning the listing of items sent by the server.
+func (c *Client) connect() (*conn, []Item, error) {
+	u, origin, err := c.url()
+	if err != nil {
+		return nil, nil, fatalError{err}
+	}
+
+	header := http.Header{}
+	header.Set("Origin", origin)
+
+	if c.opts.Password != "" {
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+c.opts.Password)))
+	}
+
+	if c.opts.Token != "" {
+		header.Set("X-Clip-Token", c.opts.Token)
+	}
+
+	ws, _, err := websocket.DefaultDialer.DialContext(c.ctx, u, header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cn := &conn{ws: ws, lost: make(chan struct{})}
+
+	err = cn.send(&pb.Message{Msg: &pb.Message_Hello{Hello: &pb.Hello{Version: ProtocolVersion, Capabilities: capabilities, ClientId: c.clientId}}})
+	if err != nil {
+		ws.Close()
+		return nil, nil, err
+	}
+
+	// a server which got the hello late took us for a legacy client, and sent the clip before the welcome
+	welcome, err := cn.recv()
+	for err == nil && (welcome.GetText() != nil || welcome.GetHdr() != nil || welcome.GetChunk() != nil) {
+		welcome, err = cn.recv()
+	}
+	if err != nil {
+		ws.Close()
+		return nil, nil, err
+	}
+	if e := welcome.GetErr(); e != nil {
+		ws.Close()
+		return nil, nil, net.NewError(e.GetCode(), e.GetDesc())
+	}
+	if welcome.GetWelcome() == nil {
+		ws.Close()
+		return nil, nil, fmt.Errorf("unexpected message: %v", welcome)
+	}
+
+	switch encrypted := welcome.GetWelcome().GetEncrypted(); {
+
+	case encrypted && c.opts.Key == nil:
+		ws.Close()
+		return nil, nil, fatalError{ErrKeyRequired}
+
+	case !encrypted && c.opts.Key != nil:
+		ws.Close()
+		return nil, nil, fatalError{ErrNotEncrypted}
+
+	}
+
+	cn.readOnly = welcome.GetWelcome().GetReadOnly()
+
+	m, err := cn.recv()
+	for err == nil && m.GetExpiry() != nil {
+		c.expire(m.GetExpiry())
+		m, err = cn.recv()
+	}
+	if err != nil {
+		ws.Close()
+		return nil, nil, err
+	}
+	if m.GetItems() == nil {
+		ws.Close()
+		return nil, nil, fatalError{ErrNoItems}
+	}
+
+	return cn, newItems(m.GetItems(), c.opts.Key), nil
+}
+
+func (c *Client) attach(cn *conn, items []Item) {
+	c.mu.Lock()
+	c.conn = cn
+	c.readOnly = cn.readOnly
+	close(c.up)
+	c.mu.Unlock()
+
+	c.update(items)
+}
+
+func (c *Client) detach(cn *conn) {
+	cn.ws.Close()
+	close(cn.lost)
+
+	c.mu.Lock()
+	c.conn = nil
+	c.up = make(chan struct{})
+	c.mu.Unlock()
+}
+
+func (c *Client) update(items []Item) {
+	c.mu.Lock()
+	c.items = items
+	c.mu.Unlock()
+
+	// run is the only sender, so the channel has room once the stale listing is dropped
+	select {
+	case <-c.updates:
+	default:
+	}
+
+	c.updates <- items
+}
+
+func (c *Client) expire(m *pb.Expiry) {
+	var end time.Time
+	if m.GetTimestamp() != 0 {
+		end = time.UnixMilli(m.GetTimestamp())
+	}
+
+	select {
+	case <-c.expiring:
+	default:
+	}
+
+	c.expiring <- end
+}
+
+func (c *Client) stop(err error) {
+	if c.ctx.Err() != nil {
+		err = ErrClosed
+	}
+
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+
+	c.cancel()
+	close(c.updates)
+	close(c.expiring)
+	close(c.done)
+}
+
+// run reads the connection until it drops, and reconnects with an exponential backoff.
+func (c *Client) run(cn *conn) {
+	for {
+		err := c.read(cn)
+		c.detach(cn)
+
+		var fatal fatalError
+		if err == nil || errors.As(err, &fatal) || c.ctx.Err() != nil {
+			c.stop(err)
+			return
+		}
+
+		var items []Item
+		cn, items, err = c.reconnect()
+		if err != nil {
+			c.stop(err)
+			return
+		}
+
+		c.attach(cn, items)
+	}
+}
+
+func (c *Client) reconnect() (*conn, []Item, error) {
+	backoff := MinBackoff
+
+	for {
+		select {
+
+		case <-time.After(backoff):
+
+		case <-c.ctx.Done():
+			return nil, nil, c.ctx.Err()
+
+		}
+
+		cn, items, err := c.connect()
+		if err == nil {
+			return cn, items, nil
+		}
+
+		var fatal fatalError
+		if errors.As(err, &fatal) || c.ctx.Err() != nil {
+			return nil, nil, err
+		}
+
+		backoff = min(backoff*2, MaxBackoff)
+	}
+}
+
+// read passes listings of items to Updates and the other messages to the running transfer. It returns
+// nil once the contents the client uploaded to a burn after reading clip were consumed, which stops it.
+func (c *Client) read(cn *conn) error {
+	for {
+		m, err := cn.recv()
+		if err != nil {
+			return err
+		}
+
+		switch m.Msg.(type) {
+
+		case *pb.Message_Items:
+			c.update(newItems(m.GetItems(), c.opts.Key))
+
+		case *pb.Message_Consumed:
+			c.mu.Lock()
+			c.consumed = true
+			c.mu.Unlock()
+
+			return nil
+
+		case *pb.Message_Expiry:
+			c.expire(m.GetExpiry())
+
+		default:
+			select {
+			case c.in <- m:
+			default:
+				// nobody is waiting for it
+			}
+
+		}
+	}
+}
+
+func (c *Client) keepAlive() {
+	ticker := time.NewTicker(KeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+
+		case <-ticker.C:
+			c.mu.Lock()
+			cn := c.conn
+			c.mu.Unlock()
+
+			if cn != nil {
+				cn.ping()
+			}
+
+		case <-c.done:
+			return
+
+		}
+	}
+}
+
+// begin waits for the previous transfer and for the connection, and drops stale messages.
+func (c *Client) begin(ctx context.Context) (*conn, error) {
+	c.op.Lock()
+
+	for {
+		c.mu.Lock()
+		cn, up := c.conn, c.up
+		c.mu.Unlock()
+
+		if cn != nil {
+			break
+		}
+
+		select {
+
+		case <-up:
+
+		case <-ctx.Done():
+			c.op.Unlock()
+			return nil, ctx.Err()
+
+		case <-c.done:
+			c.op.Unlock()
+			return nil, ErrClosed
+
+		}
+	}
+
+	for {
+		select {
+
+		case <-c.in:
+
+		default:
+			c.mu.Lock()
+			cn := c.conn
+			c.mu.Unlock()
+
+			if cn == nil {
+				c.op.Unlock()
+				return nil, ErrDisconnected
+			}
+
+			return cn, nil
+
+		}
+	}
+}
+
+// end lets the next transfer begin. A transfer that failed on the client side may leave messages in flight,
+// so the connection is dropped rather than risking the next transfer mistaking them for its own.
+func (c *Client) end(cn *conn, err error) {
+	var e *net.Error
+	if err != nil && !errors.As(err, &e) {
+		cn.ws.Close()
+	}
+
+	c.op.Unlock()
+}
+
+// recv returns the next message for the running transfer, errors sent by the server are returned as a net.Error.
+func (c *Client) recv(ctx context.Context, cn *conn) (*pb.Message, error) {
+	select {
+
+	case m := <-c.in:
+		if e := m.GetErr(); e != nil {
+			return nil, net.NewError(e.GetCode(), e.GetDesc())
+		}
+
+		return m, nil
+
+	case <-cn.lost:
+		return nil, ErrDisconnected
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	}
+}
+
+func (cn *conn) send(m *pb.Message) error {
+	cn.mu.Lock()
+	defer cn.mu.Unlock()
+
+	return cn.ws.WriteMessage(websocket.BinaryMessage, net.Out(m))
+}
+
+// ping sends a websocket ping, which costs the server nothing but resetting its deadline.
+func (cn *conn) ping() error {
+	return cn.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(KeepAliveInterval))
+}
+
+func (cn *conn) recv() (*pb.Message, error) {
+	for {
+		typ, buf, err := cn.ws.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
+
+		if typ != websocket.BinaryMessage {
+			continue
+		}
+
+		m, err := net.In(net.CID{}, buf)
+		if err != nil {
+			return nil, err
+		}
+
+		if e := m.GetErr(); e != nil && e.GetFatal() {
+			return nil, fatalError{net.NewError(e.GetCode(), e.GetDesc())}
+		}
+
+		return m.Message, nil
+	}
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"io/fs"
+
+	pb "mutclip/pkg/pb/clip"
+)
+
+// Item is an entry of a clip, holding either a text or a file. Items of encrypted clips are opened
+// with the key of the clip, Err tells why one could not be.
+type Item struct {
+	Id   string
+	Text string
+	File *File // nil for text
+	Err  error
+
+	seal *seal // nil unless the clip is encrypted
+}
+
+type File struct {
+	Name        string
+	ContentType string
+	NumChunks   int
+	Session     string
+	Digest      []byte  // SHA-256 of the whole file
+	Entries     []Entry // files of a tree, empty for a single file
+}
+
+// Entry is a file or a directory of a tree, Start and End delimit the chunks holding the file.
+type Entry struct {
+	Path  string
+	Size  int64
+	Mode  fs.FileMode
+	Dir   bool
+	Start int
+	End   int
+}
+
+func newItem(m *pb.Item, key []byte) Item {
+	item := Item{Id: m.GetId()}
+
+	if text := m.GetText(); text != nil {
+		item.Text = text.GetData()
+
+		if envelope := text.GetEnvelope(); envelope != nil {
+			item.Text = ""
+			item.seal, item.Err = openSeal(key, envelope)
+			if item.Err != nil {
+				return item
+			}
+
+			var plain []byte
+			plain, item.Err = item.seal.open(text.GetCiphertext())
+			item.Text = string(plain)
+		}
+	}
+
+	if hdr := m.GetHdr(); hdr != nil {
+		item.File = newFile(hdr)
+
+		if envelope := hdr.GetEnvelope(); envelope != nil {
+			item.seal, item.Err = openSeal(key, envelope)
+			if item.Err != nil {
+				return item
+			}
+
+			var meta metadata
+			meta, item.Err = item.seal.openMetadata(envelope.GetMetadata())
+			if item.Err == nil && meta.NumChunks != item.File.NumChunks {
+				item.Err = ErrUnsealed
+			}
+
+			item.File.Name, item.File.ContentType = meta.Filename, meta.ContentType
+		}
+	}
+
+	return item
+}
+
+func newItems(m *pb.Items, key []byte) []Item {
+	var items []Item
+	for _, item := range m.GetItems() {
+		items = append(items, newItem(item, key))
+	}
+
+	return items
+}
+
+func newFile(hdr *pb.FileHeader) *File {
+	file := &File{
+		Name:        hdr.GetFilename(),
+		ContentType: hdr.GetContentType(),
+		NumChunks:   int(hdr.GetNumChunks()),
+		Session:     hdr.GetSession(),
+		Digest:      hdr.GetDigest(),
+	}
+
+	for _, entry := range hdr.GetManifest().GetEntries() {
+		file.Entries = append(file.Entries, Entry{
+			Path:  entry.GetPath(),
+			Size:  entry.GetSize(),
+			Mode:  fs.FileMode(entry.GetMode()),
+			Dir:   entry.GetDir(),
+			Start: int(entry.GetStart()),
+			End:   int(entry.GetEnd()),
+		})
+	}
+
+	return file
+}
+
+// Top returns the last of items, which clients that only show one item display.
+func Top(items []Item) (Item, bool) {
+	if len(items) == 0 {
+		return Item{}, false
+	}
+
+	return items[len(items)-1], true
+}
+
+// Find returns the item with the given id.
+func Find(items []Item, id string) (Item, bool) {
+	for _, item := range items {
+		if item.Id == id {
+			return item, true
+		}
+	}
+
+	return Item{}, false
+}
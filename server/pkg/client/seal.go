@@ -0,0 +1,219 @@
+package client
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+
+	pb "mutclip/pkg/pb/clip"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	KeySize  = 32
+	saltSize = 16
+	kdfInfo  = "mutclip"
+)
+
+var (
+	ErrInvalidKey          = errors.New("invalid key")
+	ErrKeyRequired         = errors.New("clip is encrypted, its key is required")
+	ErrNotEncrypted        = errors.New("clip is not encrypted")
+	ErrUnsupportedEnvelope = errors.New("unsupported envelope")
+	ErrUnsealed            = errors.New("unable to open sealed content, the key is wrong or the content was tampered with")
+)
+
+// NewKey returns a random key for an encrypted clip.
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// EncodeKey encodes a key for the fragment of the URL of a clip.
+func EncodeKey(key []byte) string {
+	return base64.RawURLEncoding.EncodeToString(key)
+}
+
+func DecodeKey(s string) ([]byte, error) {
+	key, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+// seal opens and seals the contents of an item of an encrypted clip, as described by its envelope.
+type seal struct {
+	aead  cipher.AEAD
+	nonce []byte
+}
+
+// metadata is sealed in the envelope of a file, so that the server does not learn its name, nor drop
+// its last chunks unnoticed.
+type metadata struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType,omitempty"`
+	NumChunks   int    `json:"numChunks"`
+}
+
+// newSeal returns a seal for a new item, with a key derived from the key of the clip with a random salt.
+func newSeal(key []byte) (*seal, *pb.Envelope, error) {
+	salt := make([]byte, saltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, 12)
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	envelope := &pb.Envelope{
+		Cipher: pb.Cipher_CIPHER_AES_256_GCM,
+		Nonce:  nonce,
+		Kdf:    &pb.Kdf{Algorithm: pb.KdfAlgorithm_KDF_ALGORITHM_HKDF_SHA256, Salt: salt},
+	}
+
+	s, err := openSeal(key, envelope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, envelope, nil
+}
+
+func openSeal(key []byte, envelope *pb.Envelope) (*seal, error) {
+	kdf := envelope.GetKdf()
+
+	var derived []byte
+	switch kdf.GetAlgorithm() {
+
+	case pb.KdfAlgorithm_KDF_ALGORITHM_NONE:
+		derived = key
+
+	case pb.KdfAlgorithm_KDF_ALGORITHM_HKDF_SHA256:
+		derived = make([]byte, KeySize)
+
+		_, err := io.ReadFull(hkdf.New(sha256.New, key, kdf.GetSalt(), []byte(kdfInfo)), derived)
+		if err != nil {
+			return nil, err
+		}
+
+	case pb.KdfAlgorithm_KDF_ALGORITHM_PBKDF2_SHA256:
+		derived = pbkdf2.Key(key, kdf.GetSalt(), int(kdf.GetIterations()), KeySize, sha256.New)
+
+	default:
+		return nil, ErrUnsupportedEnvelope
+
+	}
+
+	var aead cipher.AEAD
+	switch envelope.GetCipher() {
+
+	case pb.Cipher_CIPHER_AES_256_GCM:
+		block, err := aes.NewCipher(derived)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err = cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+	case pb.Cipher_CIPHER_XCHACHA20_POLY1305:
+		var err error
+		aead, err = chacha20poly1305.NewX(derived)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, ErrUnsupportedEnvelope
+
+	}
+
+	if len(envelope.GetNonce()) != aead.NonceSize() {
+		return nil, ErrUnsupportedEnvelope
+	}
+
+	return &seal{aead, envelope.GetNonce()}, nil
+}
+
+// chunkNonce returns the nonce of chunk i, which is the nonce of the envelope with its last 4 bytes
+// xored with i+1, so that it differs from the one of the metadata.
+func (s *seal) chunkNonce(i int) []byte {
+	nonce := append([]byte(nil), s.nonce...)
+
+	tail := nonce[len(nonce)-4:]
+	binary.BigEndian.PutUint32(tail, binary.BigEndian.Uint32(tail)^uint32(i+1))
+
+	return nonce
+}
+
+func (s *seal) seal(data []byte) []byte {
+	return s.aead.Seal(nil, s.nonce, data, nil)
+}
+
+func (s *seal) open(data []byte) ([]byte, error) {
+	plain, err := s.aead.Open(nil, s.nonce, data, nil)
+	if err != nil {
+		return nil, ErrUnsealed
+	}
+
+	return plain, nil
+}
+
+func (s *seal) sealChunk(i int, data []byte) []byte {
+	return s.aead.Seal(nil, s.chunkNonce(i), data, nil)
+}
+
+func (s *seal) openChunk(i int, data []byte) ([]byte, error) {
+	plain, err := s.aead.Open(nil, s.chunkNonce(i), data, nil)
+	if err != nil {
+		return nil, ErrUnsealed
+	}
+
+	return plain, nil
+}
+
+func (s *seal) sealMetadata(filename string, contentType string, numChunks int) ([]byte, error) {
+	buf, err := json.Marshal(metadata{filename, contentType, numChunks})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.seal(buf), nil
+}
+
+func (s *seal) openMetadata(data []byte) (metadata, error) {
+	buf, err := s.open(data)
+	if err != nil {
+		return metadata{}, err
+	}
+
+	var m metadata
+	err = json.Unmarshal(buf, &m)
+	if err != nil {
+		return metadata{}, ErrUnsealed
+	}
+
+	return m, nil
+}
@@ -0,0 +1,268 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	pb "mutclip/pkg/pb/clip"
+)
+
+var (
+	ErrNotFile          = errors.New("item is not a file")
+	ErrNoPath           = errors.New("no such path")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// Upload describes a file to push. Add pushes it on top of the items of the clip instead of replacing them.
+type Upload struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Digest      []byte // SHA-256 of the file, checked by the server if set, but never sent to encrypted clips
+	Add         bool
+}
+
+// PushText sets the clip to text, or pushes text on top of its items if add is set.
+func (c *Client) PushText(ctx context.Context, text string, add bool) (err error) {
+	cn, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { c.end(cn, err) }()
+
+	m := &pb.Text{Data: text}
+	if c.opts.Key != nil {
+		s, envelope, err := newSeal(c.opts.Key)
+		if err != nil {
+			return err
+		}
+
+		m = &pb.Text{Ciphertext: s.seal([]byte(text)), Envelope: envelope}
+	}
+
+	if add {
+		err = cn.send(&pb.Message{Msg: &pb.Message_AddItem{AddItem: &pb.AddItem{Content: &pb.AddItem_Text{Text: m}}}})
+	} else {
+		err = cn.send(&pb.Message{Msg: &pb.Message_Text{Text: m}})
+	}
+	if err != nil {
+		return err
+	}
+
+	for {
+		m, err := c.recv(ctx, cn)
+		if err != nil {
+			return err
+		}
+
+		if m.GetAck() != nil {
+			return nil
+		}
+	}
+}
+
+// PushFile uploads u.Size bytes read from r, sending chunks as the server grants credits for them.
+// The chunks and the name of the file are sealed if the clip is encrypted.
+func (c *Client) PushFile(ctx context.Context, u Upload, r io.Reader) (err error) {
+	cn, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { c.end(cn, err) }()
+
+	numChunks := int((u.Size + ChunkSize - 1) / ChunkSize)
+
+	hdr := &pb.FileHeader{
+		Filename:    u.Name,
+		ContentType: u.ContentType,
+		NumChunks:   int32(numChunks),
+		Window:      Window,
+		Digest:      u.Digest,
+	}
+
+	var s *seal
+	if c.opts.Key != nil {
+		var envelope *pb.Envelope
+		s, envelope, err = newSeal(c.opts.Key)
+		if err != nil {
+			return err
+		}
+
+		envelope.Metadata, err = s.sealMetadata(u.Name, u.ContentType, numChunks)
+		if err != nil {
+			return err
+		}
+
+		hdr = &pb.FileHeader{NumChunks: int32(numChunks), Window: Window, Envelope: envelope}
+	}
+
+	if u.Add {
+		err = cn.send(&pb.Message{Msg: &pb.Message_AddItem{AddItem: &pb.AddItem{Content: &pb.AddItem_Hdr{Hdr: hdr}}}})
+	} else {
+		err = cn.send(&pb.Message{Msg: &pb.Message_Hdr{Hdr: hdr}})
+	}
+	if err != nil {
+		return err
+	}
+
+	credits, idx := 0, 0
+	for {
+		m, err := c.recv(ctx, cn)
+		if err != nil {
+			return err
+		}
+
+		if m.GetAck() != nil {
+			return nil
+		}
+
+		next := m.GetNextChunk()
+		if next == nil {
+			continue
+		}
+
+		credits += int(next.GetCredits())
+
+		for ; credits > 0 && idx < numChunks; credits-- {
+			data := make([]byte, min(ChunkSize, u.Size-int64(idx)*ChunkSize))
+
+			_, err := io.ReadFull(r, data)
+			if err != nil {
+				return err
+			}
+
+			if s != nil {
+				data = s.sealChunk(idx, data)
+			}
+
+			sum := sha256.Sum256(data)
+
+			err = cn.send(&pb.Message{Msg: &pb.Message_Chunk{Chunk: &pb.Chunk{Index: int32(idx), Data: data, Hash: sum[:]}}})
+			if err != nil {
+				return err
+			}
+
+			idx++
+		}
+	}
+}
+
+// Download writes the contents of a file item to w, or of the file at path if the item is a tree.
+// The digest of the whole file is only checked when no path is given. Chunks of files of encrypted
+// clips are opened before being written.
+func (c *Client) Download(ctx context.Context, item Item, path string, w io.Writer) (err error) {
+	if item.File == nil {
+		return ErrNotFile
+	}
+
+	if item.Err != nil {
+		return item.Err
+	}
+
+	start, end := 0, item.File.NumChunks
+	if path != "" {
+		found := false
+		for _, entry := range item.File.Entries {
+			if entry.Path == path && !entry.Dir {
+				start, end, found = entry.Start, entry.End, true
+			}
+		}
+
+		if !found {
+			return ErrNoPath
+		}
+	}
+
+	cn, err := c.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { c.end(cn, err) }()
+
+	err = cn.send(&pb.Message{Msg: &pb.Message_Fetch{Fetch: &pb.Fetch{Item: item.Id, Session: item.File.Session, Path: path}}})
+	if err != nil {
+		return err
+	}
+
+	var hdr *pb.FileHeader
+	for hdr == nil {
+		m, err := c.recv(ctx, cn)
+		if err != nil {
+			return err
+		}
+
+		hdr = m.GetHdr()
+	}
+
+	if start == end {
+		return nil
+	}
+
+	// the server sends as many chunks as it was granted credits, and stops once the last one is sent
+	granted := min(Window, end-start)
+
+	err = cn.send(&pb.Message{Msg: &pb.Message_NextChunk{NextChunk: &pb.NextChunk{Credits: int32(granted)}}})
+	if err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+
+	for idx := start; idx < end; {
+		m, err := c.recv(ctx, cn)
+		if err != nil {
+			return err
+		}
+
+		chunk := m.GetChunk()
+		if chunk == nil {
+			continue
+		}
+
+		if int(chunk.GetIndex()) != idx {
+			return fmt.Errorf("received chunk %v, but expected %v", chunk.GetIndex(), idx)
+		}
+
+		data := chunk.GetData()
+
+		if sum := sha256.Sum256(data); len(chunk.GetHash()) != 0 && !bytes.Equal(sum[:], chunk.GetHash()) {
+			return ErrChecksumMismatch
+		}
+
+		// the digest is the one of the sealed file
+		hash.Write(data)
+
+		if item.seal != nil {
+			data, err = item.seal.openChunk(idx, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = w.Write(data)
+		if err != nil {
+			return err
+		}
+
+		idx++
+
+		if start+granted < end {
+			granted++
+
+			err = cn.send(&pb.Message{Msg: &pb.Message_NextChunk{NextChunk: &pb.NextChunk{Index: int32(idx), Credits: 1}}})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if path == "" && len(hdr.GetDigest()) != 0 && !bytes.Equal(hash.Sum(nil), hdr.GetDigest()) {
+		return ErrChecksumMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package clipservice
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+type ArchiveFormat string
+
+const (
+	ArchiveZip   ArchiveFormat = "zip"
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+
+	ManifestFilename = "MANIFEST.json"
+
+	kindTree = "tree"
+)
+
+var ErrUnknownFormat = errors.New("unknown archive format")
+
+// Archive is a snapshot of the items of a clipboard, written as a single archive along with a manifest.
+// The blobs of the files are opened when the snapshot is taken, so that they can be read even if
+// the items are replaced meanwhile.
+type Archive struct {
+	id      ClipboardId
+	created time.Time
+	items   []Item
+	blobs   map[string]*os.File
+}
+
+type archiveManifest struct {
+	Clip    ClipboardId    `json:"clip"`
+	Created time.Time      `json:"created"`
+	Items   []archivedItem `json:"items"`
+}
+
+type archivedItem struct {
+	Id       string          `json:"id"`
+	Kind     string          `json:"kind"`
+	Name     string          `json:"name"`
+	Uploader string          `json:"uploader"`
+	Created  time.Time       `json:"created"`
+	Size     int64           `json:"size"`
+	Digest   string          `json:"sha256"`
+	Files    []archivedEntry `json:"files,omitempty"`
+}
+
+type archivedEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Mode   uint32 `json:"mode"`
+	Dir    bool   `json:"dir,omitempty"`
+	Digest string `json:"sha256,omitempty"`
+}
+
+// archiveFile is a file or a directory added to the archive.
+type archiveFile struct {
+	name     string
+	mode     fs.FileMode
+	modified time.Time
+	size     int64
+	r        io.Reader
+}
+
+func ParseArchiveFormat(format string) (ArchiveFormat, error) {
+	switch format {
+
+	case "", "zip":
+		return ArchiveZip, nil
+
+	case "tar.gz", "tgz":
+		return ArchiveTarGz, nil
+
+	default:
+		return "", ErrUnknownFormat
+
+	}
+}
+
+func (s *ClipboardService) Archive(id ClipboardId) (*Archive, error) {
+	clip := s.getClip(id)
+	if clip == nil {
+		return nil, ErrInvalidClipId
+	}
+
+	if clip.settings.encrypted {
+		return nil, ErrSealed
+	}
+
+	if clip.settings.burn {
+		return nil, ErrBurnAfterReading
+	}
+
+	clip.mu.Lock()
+	defer clip.mu.Unlock()
+
+	a := &Archive{
+		id:      id,
+		created: time.Now(),
+		items:   clip.items,
+		blobs:   make(map[string]*os.File),
+	}
+
+	for _, item := range a.items {
+		file, ok := item.content.(ContentFile)
+		if !ok {
+			continue
+		}
+
+		blob, err := s.openBlob(id, file.blob)
+		if err != nil {
+			a.Close()
+			return nil, err
+		}
+
+		a.blobs[item.id] = blob
+	}
+
+	return a, nil
+}
+
+func (a *Archive) Close() error {
+	var errs []error
+	for _, blob := range a.blobs {
+		errs = append(errs, blob.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// Write streams the archive to w. Files are read from the blobs as they are written, and their
+// checksums are computed on the way, which is why the manifest comes last.
+func (a *Archive) Write(w io.Writer, format ArchiveFormat) error {
+	var add func(f archiveFile) error
+	var finish func() error
+
+	switch format {
+
+	case ArchiveZip:
+		zw := zip.NewWriter(w)
+
+		add = func(f archiveFile) error {
+			hdr := &zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: f.modified}
+			if f.mode.IsDir() {
+				hdr.Name += "/"
+				hdr.Method = zip.Store
+			}
+			hdr.SetMode(f.mode)
+
+			fw, err := zw.CreateHeader(hdr)
+			if err != nil {
+				return err
+			}
+
+			if f.r == nil {
+				return nil
+			}
+
+			_, err = io.Copy(fw, f.r)
+			return err
+		}
+
+		finish = zw.Close
+
+	case ArchiveTarGz:
+		gw := gzip.NewWriter(w)
+		tw := tar.NewWriter(gw)
+
+		add = func(f archiveFile) error {
+			hdr := &tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     f.name,
+				Size:     f.size,
+				Mode:     int64(f.mode.Perm()),
+				ModTime:  f.modified,
+			}
+			if f.mode.IsDir() {
+				hdr.Typeflag = tar.TypeDir
+				hdr.Name += "/"
+			}
+
+			err := tw.WriteHeader(hdr)
+			if err != nil {
+				return err
+			}
+
+			if f.r == nil {
+				return nil
+			}
+
+			_, err = io.Copy(tw, f.r)
+			return err
+		}
+
+		finish = func() error {
+			err := tw.Close()
+			if err != nil {
+				return err
+			}
+
+			return gw.Close()
+		}
+
+	default:
+		return ErrUnknownFormat
+
+	}
+
+	manifest := archiveManifest{Clip: a.id, Created: a.created}
+	names := make(map[string]struct{})
+
+	for i, item := range a.items {
+		archived := archivedItem{
+			Id:       item.id,
+			Uploader: item.author.String(),
+			Created:  item.created,
+		}
+
+		switch content := item.content.(type) {
+
+		case ContentText:
+			archived.Kind = kindText
+			archived.Name = uniqueName(names, fmt.Sprintf("text-%v.txt", i+1), item.id)
+			archived.Size = int64(len(content.data))
+
+			digest := sha256.Sum256([]byte(content.data))
+			archived.Digest = hex.EncodeToString(digest[:])
+
+			err := add(archiveFile{
+				name:     archived.Name,
+				mode:     0o644,
+				modified: item.created,
+				size:     archived.Size,
+				r:        strings.NewReader(content.data),
+			})
+			if err != nil {
+				return err
+			}
+
+		case ContentFile:
+			archived.Kind = kindFile
+			archived.Name = uniqueName(names, fileName(content.filename), item.id)
+			archived.Size = content.offsets[len(content.offsets)-1]
+			archived.Digest = hex.EncodeToString(content.digest)
+
+			blob := a.blobs[item.id]
+
+			if len(content.entries) == 0 {
+				err := add(archiveFile{
+					name:     archived.Name,
+					mode:     0o644,
+					modified: item.created,
+					size:     archived.Size,
+					r:        io.NewSectionReader(blob, 0, archived.Size),
+				})
+				if err != nil {
+					return err
+				}
+
+				break
+			}
+
+			archived.Kind = kindTree
+
+			err := add(archiveFile{name: archived.Name, mode: fs.ModeDir | 0o755, modified: item.created})
+			if err != nil {
+				return err
+			}
+
+			for _, entry := range content.entries {
+				mode := fs.FileMode(entry.mode)
+				if entry.dir {
+					mode |= fs.ModeDir
+				}
+
+				archivedEntry := archivedEntry{Path: entry.path, Size: entry.size, Mode: entry.mode, Dir: entry.dir}
+
+				f := archiveFile{
+					name:     path.Join(archived.Name, entry.path),
+					mode:     mode,
+					modified: item.created,
+					size:     entry.size,
+				}
+
+				hash := sha256.New()
+				if !entry.dir {
+					f.r = io.TeeReader(io.NewSectionReader(blob, entry.offset, entry.size), hash)
+				}
+
+				err := add(f)
+				if err != nil {
+					return err
+				}
+
+				if !entry.dir {
+					archivedEntry.Digest = hex.EncodeToString(hash.Sum(nil))
+				}
+
+				archived.Files = append(archived.Files, archivedEntry)
+			}
+
+		default:
+			panic("impossible")
+
+		}
+
+		manifest.Items = append(manifest.Items, archived)
+	}
+
+	buf, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = add(archiveFile{
+		name:     uniqueName(names, ManifestFilename, a.id),
+		mode:     0o644,
+		modified: a.created,
+		size:     int64(len(buf)),
+		r:        strings.NewReader(string(buf)),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("[%v] ARCHIVE %v : %v items", a.id, format, len(a.items))
+
+	return finish()
+}
+
+// fileName strips an uploaded filename down to a name which is safe to extract.
+func fileName(filename string) string {
+	name := path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	if name == "." || name == ".." || name == "/" {
+		return "file"
+	}
+
+	return name
+}
+
+// uniqueName returns name, or name prefixed with id if it is already used in the archive.
+func uniqueName(names map[string]struct{}, name string, id string) string {
+	if _, ok := names[name]; ok {
+		name = id + "-" + name
+	}
+
+	names[name] = struct{}{}
+
+	return name
+}
@@ -0,0 +1,116 @@
+package clipservice
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+)
+
+func (s *ClipboardService) createBlob(id ClipboardId) (*os.File, string, error) {
+	dir := s.store.BlobDir(id)
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := uuid.NewString()
+
+	f, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return f, name, nil
+}
+
+func (s *ClipboardService) openBlob(id ClipboardId, name string) (*os.File, error) {
+	return os.Open(filepath.Join(s.store.BlobDir(id), name))
+}
+
+func (s *ClipboardService) removeBlob(id ClipboardId, name string) error {
+	return os.Remove(filepath.Join(s.store.BlobDir(id), name))
+}
+
+// discard releases the storage held by contents that are no longer referenced by the clipboard.
+func (s *ClipboardService) discard(id ClipboardId, contents []Content) {
+	clip := s.getClip(id)
+
+	clip.mu.Lock()
+	defer clip.mu.Unlock()
+
+	for _, content := range contents {
+		file, ok := content.(ContentFile)
+		if !ok || file.blob == "" || clip.referenced(file.blob) {
+			continue
+		}
+
+		err := s.removeBlob(id, file.blob)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+}
+
+func (f ContentFile) header(item string) *pb.FileHeader {
+	return &pb.FileHeader{
+		Filename:    f.filename,
+		ContentType: f.contentType,
+		NumChunks:   int32(f.numChunks),
+		Session:     f.session,
+		Digest:      f.digest,
+		Item:        item,
+		Manifest:    f.manifest(),
+		Envelope:    f.envelope.message(),
+	}
+}
+
+// chunkRange returns the chunks requested by fetch, a nil fetch or a zero end select the whole file.
+// A path selects one of the files of a tree.
+func (f ContentFile) chunkRange(fetch *pb.Fetch) (int, int, error) {
+	if fetch.GetSession() != "" && fetch.GetSession() != f.session {
+		return 0, 0, ErrFileChanged
+	}
+
+	if p := fetch.GetPath(); p != "" {
+		entry, ok := f.findEntry(p)
+		if !ok || entry.dir {
+			return 0, 0, ErrInvalidPath
+		}
+
+		start, end := f.entryChunks(entry)
+		return start, end, nil
+	}
+
+	start, end := int(fetch.GetStart()), int(fetch.GetEnd())
+	if end == 0 {
+		end = f.numChunks
+	}
+
+	if start < 0 || end > f.numChunks || start > end {
+		return 0, 0, ErrInvalidRange
+	}
+
+	return start, end, nil
+}
+
+func (f ContentFile) readChunk(r io.ReaderAt, idx int) ([]byte, error) {
+	if idx < 0 || idx >= f.numChunks || idx+1 >= len(f.offsets) {
+		return nil, fmt.Errorf("chunk %v is out of range", idx)
+	}
+
+	buf := make([]byte, f.offsets[idx+1]-f.offsets[idx])
+
+	_, err := r.ReadAt(buf, f.offsets[idx])
+	if err != nil {
+		return nil, err
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,122 @@
+package clipservice
+
+import (
+	"bytes"
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+)
+
+// BurnGrace is how long a burnt clipboard lives on, so that the reader gets the last of the contents
+// and the uploader gets notified before the connections are closed.
+const BurnGrace = time.Second
+
+var (
+	ErrConsumed         = net.NewError(pb.ErrorCode_ERROR_CODE_BURN_AFTER_READING, "contents were burnt after reading")
+	ErrBurnAfterReading = net.NewError(pb.ErrorCode_ERROR_CODE_BURN_AFTER_READING, "contents of burn after reading clipboards are only sent to clients")
+)
+
+// consume claims item for cid if the clipboard burns after reading, holds item and cid is neither
+// its uploader nor a client pushing contents over HTTP. Only the first claim succeeds. Must be called with
+// clip.mu held.
+func (clip *Clipboard) consume(cid net.CID, item Item) bool {
+	if !clip.settings.burn || clip.burnt {
+		return false
+	}
+
+	if _, ok := clip.findItem(item.id); !ok {
+		return false
+	}
+
+	a, ok := clip.clients.Load(cid)
+	if !ok {
+		return false
+	}
+
+	client, ok := a.(*Client)
+	if !ok {
+		panic("impossible")
+	}
+
+	if !client.sync || bytes.Equal(client.uploader(), item.uploader) {
+		return false
+	}
+
+	clip.burnt = true
+
+	return true
+}
+
+// consumeTexts claims the first of the texts of the clipboard cid may consume, since the listing
+// of items sent to clients which agreed on CAPABILITY_ITEMS holds all of them. Must be called with
+// clip.mu held.
+func (clip *Clipboard) consumeTexts(cid net.CID) (Item, bool) {
+	for _, item := range clip.items {
+		if _, text := item.content.(ContentText); text && clip.consume(cid, item) {
+			return item, true
+		}
+	}
+
+	return Item{}, false
+}
+
+// burn deletes the contents of the clipboard once item was consumed by cid, notifies the clients
+// its uploader is connected with and ends the clipboard. Clients awaiting an acknowledgement are
+// notified by syncClip once they got it.
+func (s *ClipboardService) burn(id ClipboardId, cid net.CID, item Item) {
+	clip := s.getClip(id)
+
+	log.Infof("[%v] BURN %v by %v", id, item.id, cid)
+
+	clip.mu.Lock()
+	clip.items = nil
+	clip.history = nil
+	clip.mu.Unlock()
+
+	err := s.store.Delete(id)
+	if err != nil {
+		log.Error(err)
+	}
+
+	clip.clients.Range(func(key, value any) bool {
+		uploader, ok := key.(net.CID)
+		if !ok {
+			panic("impossible")
+		}
+
+		client, ok := value.(*Client)
+		if !ok {
+			panic("impossible")
+		}
+
+		if !client.sync || !bytes.Equal(client.uploader(), item.uploader) {
+			return true
+		}
+
+		clip.mu.Lock()
+		acking := clip.acking[uploader] > 0
+		if acking {
+			clip.owed[uploader] = item.id
+		}
+		clip.mu.Unlock()
+
+		if !acking {
+			s.consumed(id, uploader, item.id)
+		}
+
+		return true
+	})
+
+	time.AfterFunc(BurnGrace, clip.cancel)
+}
+
+// consumed tells uploader that item was consumed.
+func (s *ClipboardService) consumed(id ClipboardId, uploader net.CID, item string) {
+	err := s.getClip(id).router.Send(uploader, &pb.Message{Msg: &pb.Message_Consumed{Consumed: &pb.Consumed{Item: item}}})
+	if err != nil {
+		log.Errorf("unable to notify uploader %v: %v", uploader, err)
+	}
+}
@@ -0,0 +1,96 @@
+package clipservice
+
+import (
+	"testing"
+
+	"mutclip/pkg/net"
+
+	"github.com/google/uuid"
+)
+
+func TestConsume(t *testing.T) {
+	uploader := &Client{Cid: net.CID(uuid.New()), role: RoleEditor, sync: true, id: hashToken("uploader")}
+	item := newItem(ContentText{}, uploader.Cid, uploader.uploader())
+
+	tests := []struct {
+		name   string
+		burn   bool
+		burnt  bool
+		items  []Item
+		reader *Client
+		want   bool
+	}{
+		{
+			name:   "reader with the same token",
+			burn:   true,
+			items:  []Item{item},
+			reader: &Client{Cid: net.CID(uuid.New()), role: RoleEditor, sync: true, id: hashToken("reader")},
+			want:   true,
+		},
+		{
+			name:   "reader without an id",
+			burn:   true,
+			items:  []Item{item},
+			reader: &Client{Cid: net.CID(uuid.New()), role: RoleViewer, sync: true},
+			want:   true,
+		},
+		{
+			name:   "item under the top one",
+			burn:   true,
+			items:  []Item{item, newItem(ContentText{}, uploader.Cid, uploader.uploader())},
+			reader: &Client{Cid: net.CID(uuid.New()), role: RoleViewer, sync: true},
+			want:   true,
+		},
+		{
+			name:   "uploader",
+			burn:   true,
+			items:  []Item{item},
+			reader: uploader,
+		},
+		{
+			name:   "uploader after reconnecting",
+			burn:   true,
+			items:  []Item{item},
+			reader: &Client{Cid: net.CID(uuid.New()), role: RoleEditor, sync: true, id: hashToken("uploader")},
+		},
+		{
+			name:   "client pushing over HTTP",
+			burn:   true,
+			items:  []Item{item},
+			reader: &Client{Cid: net.CID(uuid.New()), role: RoleEditor},
+		},
+		{
+			name:   "clipboard which does not burn",
+			items:  []Item{item},
+			reader: &Client{Cid: net.CID(uuid.New()), role: RoleViewer, sync: true},
+		},
+		{
+			name:   "clipboard already burnt",
+			burn:   true,
+			burnt:  true,
+			items:  []Item{item},
+			reader: &Client{Cid: net.CID(uuid.New()), role: RoleViewer, sync: true},
+		},
+		{
+			name:   "item no longer held",
+			burn:   true,
+			items:  []Item{newItem(ContentText{}, uploader.Cid, uploader.uploader())},
+			reader: &Client{Cid: net.CID(uuid.New()), role: RoleViewer, sync: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip := &Clipboard{settings: Settings{burn: tt.burn}, burnt: tt.burnt, items: tt.items}
+			clip.clients.Store(tt.reader.Cid, tt.reader)
+
+			if got := clip.consume(tt.reader.Cid, item); got != tt.want {
+				t.Fatalf("consume() = %v, want %v", got, tt.want)
+			}
+
+			if tt.want && clip.consume(tt.reader.Cid, item) {
+				t.Errorf("consumed twice")
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package clipservice
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"math/rand/v2"
+	"slices"
 	"strconv"
 	"sync"
 	"time"
@@ -16,22 +19,79 @@ import (
 )
 
 const (
-	ClipDeadline = time.Minute * 2
-	alphabet     = "abcdefghijklmnopqrstuvwxyz"
+	ClipDeadline  = time.Minute * 2
+	HelloDeadline = time.Second * 3
+	MaxWindow     = 8 // maximum number of chunks in flight during a file transfer
+	alphabet      = "abcdefghijklmnopqrstuvwxyz"
+
+	ProtocolVersion    = 1
+	MinProtocolVersion = 1
 )
 
+// capabilities are the optional protocol features supported by the server.
+var capabilities = []pb.Capability{
+	pb.Capability_CAPABILITY_WINDOWING,
+	pb.Capability_CAPABILITY_CHECKSUMS,
+	pb.Capability_CAPABILITY_RESUME,
+	pb.Capability_CAPABILITY_COMPRESSION,
+	pb.Capability_CAPABILITY_ITEMS,
+	pb.Capability_CAPABILITY_ENCRYPTION,
+	pb.Capability_CAPABILITY_EXPIRY,
+}
+
 type ClipboardService struct {
-	clips sync.Map
+	clips  sync.Map
+	store  ContentStore
+	policy Policy
 }
 
 type ClipboardId = string
 
+// Options are chosen when a clipboard is generated.
+type Options struct {
+	Password         string // empty for no password
+	Encrypted        bool   // contents are sealed by the clients, see Envelope
+	BurnAfterReading bool   // the clipboard ends once a client other than the uploader has received its contents
+
+	TTL     time.Duration // how long the clipboard lives on once the last client has left, 0 for the default of the policy
+	Expires time.Time     // time at which the clipboard ends, zero for never
+}
+
+// Settings are fixed when a clipboard is generated, and stored along with its history.
+type Settings struct {
+	password    *Password // nil if the clipboard is not protected
+	encrypted   bool
+	burn        bool // burn after reading
+	ttl         time.Duration
+	expires     time.Time // zero for never
+	editorToken []byte    // hash of the token granting RoleEditor, nil if the clipboard has no tokens
+	viewerToken []byte
+}
+
 type Clipboard struct {
-	router  *net.Router
-	content Content
-	clients map[net.CID]struct{} // FIXME
-	ctx     context.Context
-	cancel  context.CancelFunc
+	router   *net.Router
+	settings Settings
+	items    []Item // never modified in place, as versions share them
+	history  []Version
+	upload   *upload
+	burnt    bool               // the contents of a burn after reading clipboard were consumed
+	acking   map[net.CID]int    // syncs in progress per client, each of which ends with an acknowledgement
+	owed     map[net.CID]string // items consumed while their uploaders awaited an acknowledgement
+	clients  sync.Map
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	mu sync.Mutex // guards items, history, upload, burnt, acking and owed
+
+	authMu    sync.Mutex           // guards throttles and verified
+	throttles map[string]*throttle // by client address, see throttleKey
+	verified  []byte               // digest of the password once a client sent it, nil until then
+
+	lifeMu  sync.Mutex // guards present, left and warned
+	present int        // number of connected clients and subscribers
+	left    time.Time  // when the last of them left
+	warned  time.Time  // end the clients were last warned of, zero if they were not
+	changed chan struct{}
 }
 
 type Client struct {
@@ -42,33 +102,84 @@ type Client struct {
 
 	In  chan net.InMessage
 	Out chan net.OutMessage
+
+	role    Role
+	sync    bool          // false for clients which only push contents over HTTP, which are never synced
+	hello   chan struct{} // closed once the client has said hello, or sent anything else first
+	mu      sync.Mutex
+	version int32
+	caps    map[pb.Capability]struct{}
+	id      []byte // hash of the id the client said hello with, which outlives its connection
 }
 
 type Content any
 
 type ContentText struct {
-	data string
+	data     string    // ciphertext if the text is sealed in an envelope
+	envelope *Envelope // nil unless the clipboard is encrypted
 }
 
 type ContentFile struct {
 	ready          bool
-	chunks         [][]byte
+	session        string
+	blob           string
+	offsets        []int64 // offsets[i] is where chunk i starts in the blob, the last one is the size of the blob
+	digest         []byte  // SHA-256 of the whole file
 	nextChunkIndex int
 	numChunks      int
 	contentType    string
 	filename       string
+	entries        []Entry   // files of a tree uploaded with a manifest
+	envelope       *Envelope // nil unless the clipboard is encrypted, the chunks are then sealed
 }
 
 var (
-	ErrInvalidClipId      = errors.New("invalid clipboard id")
+	ErrInvalidClipId      = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_CLIP, "invalid clipboard id")
 	ErrClientDisconnected = errors.New("client disconnected while sending file")
+	ErrInvalidRange       = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_RANGE, "invalid chunk range")
+	ErrFileChanged        = net.NewError(pb.ErrorCode_ERROR_CODE_FILE_CHANGED, "file has changed")
+	ErrChecksumMismatch   = net.NewError(pb.ErrorCode_ERROR_CODE_CHECKSUM_MISMATCH, "checksum mismatch")
+	ErrProtocolMismatch   = net.NewError(pb.ErrorCode_ERROR_CODE_PROTOCOL_MISMATCH, "protocol version is not supported")
+	ErrBusy               = net.NewError(pb.ErrorCode_ERROR_CODE_BUSY, "clipboard is busy receiving a file")
+	ErrUploadAborted      = net.NewError(pb.ErrorCode_ERROR_CODE_UPLOAD_ABORTED, "upload aborted")
+	ErrInvalidChunk       = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_CHUNK, "invalid chunk")
+	ErrInvalidVersion     = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_VERSION, "invalid version")
+	ErrInvalidItem        = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_ITEM, "invalid item")
 )
 
-func NewService() *ClipboardService {
-	return &ClipboardService{}
+func NewService(store ContentStore, policy Policy) *ClipboardService {
+	return &ClipboardService{store: store, policy: policy}
 }
 
-func (s *ClipboardService) Generate(ctx context.Context) ClipboardId {
+// Generate creates a clipboard along with the tokens granting its roles.
+func (s *ClipboardService) Generate(ctx context.Context, opts Options) (ClipboardId, Tokens, error) {
+	ttl, expires, err := s.policy.lifetime(opts.TTL, opts.Expires)
+	if err != nil {
+		return "", Tokens{}, err
+	}
+
+	settings := Settings{encrypted: opts.Encrypted, burn: opts.BurnAfterReading, ttl: ttl, expires: expires}
+	if opts.Password != "" {
+		password, err := newPassword(opts.Password)
+		if err != nil {
+			return "", Tokens{}, err
+		}
+
+		settings.password = password
+	}
+
+	var tokens Tokens
+
+	tokens.Editor, settings.editorToken, err = newToken()
+	if err != nil {
+		return "", Tokens{}, err
+	}
+
+	tokens.Viewer, settings.viewerToken, err = newToken()
+	if err != nil {
+		return "", Tokens{}, err
+	}
+
 	id := ""
 	for {
 		var parts []any
@@ -88,20 +199,66 @@ func (s *ClipboardService) Generate(ctx context.Context) ClipboardId {
 		}
 	}
 
+	s.newClip(ctx, id, settings, []Version{{id: 1, created: time.Now()}})
+	s.save(id)
+
+	log.Infof("* GEN %v", id)
+
+	return id, tokens, nil
+}
+
+func (s *ClipboardService) Restore(ctx context.Context) ([]ClipboardId, error) {
+	ids, err := s.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var restored []ClipboardId
+	for _, id := range ids {
+		settings, history, err := s.store.Load(id)
+		if err != nil {
+			log.Errorf("unable to restore %v: %v", id, err)
+			continue
+		}
+
+		if len(history) == 0 {
+			log.Errorf("unable to restore %v: empty history", id)
+			continue
+		}
+
+		s.newClip(ctx, id, settings, history)
+		log.Infof("* RESTORE %v", id)
+
+		restored = append(restored, id)
+	}
+
+	return restored, nil
+}
+
+func (s *ClipboardService) newClip(ctx context.Context, id ClipboardId, settings Settings, history []Version) {
+	// clipboards stored before their TTL was chosen
+	if settings.ttl == 0 {
+		settings.ttl = s.policy.DefaultTTL
+	}
+
 	clipCtx, clipCancel := context.WithCancel(ctx)
 
 	router := net.NewRouter(clipCtx)
 
 	clipboard := &Clipboard{
-		router:  router,
-		content: ContentText{},
-		clients: make(map[net.CID]struct{}),
-		ctx:     clipCtx,
-		cancel:  clipCancel,
+		router:   router,
+		settings: settings,
+		items:    slices.Clone(history[len(history)-1].items),
+		history:  history,
+		acking:   make(map[net.CID]int),
+		owed:     make(map[net.CID]string),
+		ctx:      clipCtx,
+		cancel:   clipCancel,
+		left:     time.Now(),
+		changed:  make(chan struct{}, 1),
 	}
 
 	s.clips.Store(id, clipboard)
-	log.Infof("* GEN %v", id)
 
 	go func() {
 		<-clipCtx.Done()
@@ -109,10 +266,14 @@ func (s *ClipboardService) Generate(ctx context.Context) ClipboardId {
 		time.Sleep(time.Millisecond * 10)
 
 		s.clips.Delete(id)
+
+		err := s.store.Delete(id)
+		if err != nil {
+			log.Error(err)
+		}
+
 		log.Infof("* END %v", id)
 	}()
-
-	return id
 }
 
 func (s *ClipboardService) getClip(id ClipboardId) *Clipboard {
@@ -133,7 +294,14 @@ func (s *ClipboardService) Check(id ClipboardId) bool {
 	return s.getClip(id) != nil
 }
 
-func (s *ClipboardService) Connect(id ClipboardId, ctx context.Context) (*Client, error) {
+// Connect adds a client with the role returned by Authorize to the clipboard.
+func (s *ClipboardService) Connect(id ClipboardId, ctx context.Context, role Role) (*Client, error) {
+	return s.connect(id, ctx, role, true)
+}
+
+// connect adds a client to the clipboard, sync tells whether it is sent the contents of the clipboard
+// once it has said hello.
+func (s *ClipboardService) connect(id ClipboardId, ctx context.Context, role Role, sync bool) (*Client, error) {
 	clip := s.getClip(id)
 	if clip == nil {
 		return nil, ErrInvalidClipId
@@ -151,10 +319,14 @@ func (s *ClipboardService) Connect(id ClipboardId, ctx context.Context) (*Client
 		Cid:     cid,
 		In:      clip.router.Source,
 		Out:     out,
+		role:    role,
+		sync:    sync,
+		hello:   make(chan struct{}),
 	}
 
-	clip.clients[cid] = struct{}{}
-	log.Infof("[%v] + %v", id, cid)
+	clip.clients.Store(cid, client)
+	clip.join()
+	log.Infof("[%v] + %v : %v", id, cid, role)
 
 	go func() {
 		select {
@@ -170,128 +342,386 @@ func (s *ClipboardService) Connect(id ClipboardId, ctx context.Context) (*Client
 
 		close(out)
 
-		delete(clip.clients, cid)
+		clip.clients.Delete(cid)
+		clip.leave()
 		log.Infof("[%v] - %v", id, cid)
 	}()
 
+	if !sync {
+		return client, nil
+	}
+
 	go func() {
 		time.Sleep(time.Millisecond)
 
-		err := s.syncClient(id, cid)
+		select {
+
+		case <-client.hello:
+
+		case <-time.After(s.policy.HelloDeadline):
+
+		case <-clientCtx.Done():
+			return
+
+		}
+
+		// clients unable to open sealed contents would show them as empty
+		if clip.settings.encrypted && !client.Capable(pb.Capability_CAPABILITY_ENCRYPTION) {
+			log.Errorf("client %v does not support encryption", cid)
+			clip.router.Send(cid, net.Fatal(ErrEncryptionRequired))
+			client.Cancel()
+			return
+		}
+
+		err := s.syncClient(id, cid, nil)
 		if err != nil {
 			log.Error(err)
 		}
+
+		// clients joining after the others were warned are warned as well
+		clip.lifeMu.Lock()
+		warned := clip.warned
+		clip.lifeMu.Unlock()
+
+		if !warned.IsZero() && client.Capable(pb.Capability_CAPABILITY_EXPIRY) {
+			clip.router.Send(cid, expiryMessage(warned))
+		}
 	}()
 
 	return client, nil
 }
 
-func (s *ClipboardService) syncClient(id ClipboardId, cid net.CID) error {
+func (s *ClipboardService) getClient(id ClipboardId, cid net.CID) *Client {
+	clip := s.getClip(id)
+	if clip == nil {
+		return nil
+	}
+
+	a, ok := clip.clients.Load(cid)
+	if !ok {
+		return nil
+	}
+
+	client, ok := a.(*Client)
+	if !ok {
+		panic("impossible")
+	}
+
+	return client
+}
+
+// Role returns the role of the client, a client which is gone is a viewer.
+func (c *Client) Role() Role {
+	if c == nil {
+		return RoleViewer
+	}
+
+	return c.role
+}
+
+// uploader returns what identifies the client as the uploader of an item: the id it said hello with,
+// which it keeps when it reconnects, or its connection for clients which have none. A client which is
+// gone has none.
+func (c *Client) uploader() []byte {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.id != nil {
+		return c.id
+	}
+
+	cid := c.Cid
+	return cid[:]
+}
+
+// greet lets the client be synced, once it has said hello or sent its first message. Legacy clients
+// never say hello, so a first message of another kind tells them apart without waiting for HelloDeadline.
+func (c *Client) greet() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.hello:
+	default:
+		close(c.hello)
+	}
+}
+
+// Version returns the protocol version negotiated with the client, or zero if the client has not said hello.
+func (c *Client) Version() int32 {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.version
+}
+
+// Capable reports whether the capability was agreed on during the handshake.
+func (c *Client) Capable(capability pb.Capability) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.caps[capability]
+	return ok
+}
+
+// syncClient sends the items of the clipboard to cid. Clients which agreed on CAPABILITY_ITEMS get
+// the listing of items, unless fetch names one of them; others get the top item. If the item is a file,
+// only the chunks requested by fetch are sent, a nil fetch requests the whole file.
+//
+// Burn after reading clipboards are burnt once a client other than the uploader was sent one of their
+// texts, listings included, or one of their files. Texts are claimed before they are sent, so that
+// no other client gets them, files once their last chunk was sent.
+func (s *ClipboardService) syncClient(id ClipboardId, cid net.CID, fetch *pb.Fetch) error {
 	clip := s.getClip(id)
 	r := clip.router
 
-	switch content := clip.content.(type) {
+	clip.mu.Lock()
 
-	case ContentText:
-		text := "<empty>"
-		if content.data != "" {
-			text = content.data
+	if clip.burnt {
+		clip.mu.Unlock()
+
+		r.Send(cid, net.Fatal(ErrConsumed))
+		return ErrConsumed
+	}
+
+	if fetch == nil && s.getClient(id, cid).Capable(pb.Capability_CAPABILITY_ITEMS) {
+		items, n := clip.itemsMessage(), len(clip.items)
+
+		consumed, burn := clip.consumeTexts(cid)
+
+		clip.mu.Unlock()
+
+		log.Infof("[%v] SYNC -> %v : ITEMS %v", id, cid, n)
+
+		err := r.Send(cid, items)
+		if burn {
+			s.burn(id, cid, consumed)
+		}
+
+		return err
+	}
+
+	item := topItem(clip.items)
+	if fetch.GetItem() != "" {
+		idx, ok := clip.findItem(fetch.GetItem())
+		if !ok {
+			clip.mu.Unlock()
+
+			r.Send(cid, net.Err(ErrInvalidItem))
+			return ErrInvalidItem
 		}
-		log.Infof("[%v] SYNC -> %v : TXT %v", id, cid, text)
 
-		return r.Send(cid, &pb.Message{Msg: &pb.Message_Text{Text: &pb.Text{Data: content.data}}})
+		item = clip.items[idx]
+	}
+
+	_, text := item.content.(ContentText)
+	burn := text && clip.consume(cid, item)
 
-	case ContentFile:
-		log.Infof("[%v] SYNC -> %v : FILE %v/%v", id, cid, content.filename, content.numChunks)
+	clip.mu.Unlock()
 
-		if !content.ready {
-			log.Warnf("[%v] SYNC -> %v : FILE not ready", id, cid)
-			return nil
+	switch content := item.content.(type) {
+
+	case ContentText:
+		log.Infof("[%v] SYNC -> %v : TXT %v", id, cid, content)
+
+		err := r.Send(cid, &pb.Message{Msg: &pb.Message_Text{Text: content.message(item.id)}})
+		if burn {
+			s.burn(id, cid, item)
 		}
 
-		tun, err := r.Tunnel(cid)
-		if err != nil {
+		return err
+
+	case ContentFile:
+		read, err := s.sendFile(id, cid, item.id, content, fetch)
+		if !read {
 			return err
 		}
-		defer tun.Cancel()
 
-		tun.Out <- &pb.Message{Msg: &pb.Message_Hdr{Hdr: &pb.FileHeader{
-			Filename:    content.filename,
-			ContentType: content.contentType,
-			NumChunks:   int32(content.numChunks),
-		}}}
+		clip.mu.Lock()
+		burn := clip.consume(cid, item)
+		clip.mu.Unlock()
+
+		if burn {
+			s.burn(id, cid, item)
+		}
+
+		return err
+
+	default:
+		panic("impossible")
+
+	}
+}
+
+// sendFile sends the chunks of content requested by fetch, and reports whether the client has read
+// the file, that is was sent its last chunk or one of the files of its tree.
+func (s *ClipboardService) sendFile(id ClipboardId, cid net.CID, item string, content ContentFile, fetch *pb.Fetch) (bool, error) {
+	r := s.getClip(id).router
+
+	log.Infof("[%v] SYNC -> %v : FILE %v/%v", id, cid, content.filename, content.numChunks)
+
+	idx, end, err := content.chunkRange(fetch)
+	if err != nil {
+		r.Send(cid, net.Err(err))
+		return false, err
+	}
+
+	path := fetch.GetPath()
+
+	blob, err := s.openBlob(id, content.blob)
+	if err != nil {
+		return false, err
+	}
+	defer blob.Close()
 
-		idx := 0
-		for m := range tun.In {
-			if m.GetNextChunk() == nil {
-				log.Errorf("unexpected message while sending file: %v", m)
-				tun.Out <- net.Err(fmt.Errorf("unexpected message"))
+	tun, err := r.Tunnel(cid)
+	if err != nil {
+		return false, err
+	}
+	defer tun.Cancel()
+
+	tun.Out <- &pb.Message{Msg: &pb.Message_Hdr{Hdr: content.header(item)}}
+
+	if idx == end {
+		log.Infof("[%v] SYNC -> %v : OK", id, cid)
+		return path != "" || end == content.numChunks, nil
+	}
+
+	client := s.getClient(id, cid)
+	checksums := client.Version() == 0 || client.Capable(pb.Capability_CAPABILITY_CHECKSUMS)
+	compression := client.Capable(pb.Capability_CAPABILITY_COMPRESSION)
+
+	credits := 0
+	for m := range tun.In {
+		if fetch := m.GetFetch(); fetch != nil {
+			start, stop, err := content.chunkRange(fetch)
+			if err != nil {
+				log.Error(err)
+				tun.Out <- net.Err(err)
 				continue
 			}
 
+			log.Infof("[%v] SYNC -> %v : FETCH %v..%v", id, cid, start+1, stop)
+
+			idx, end, path = start, stop, fetch.GetPath()
+			continue
+		}
+
+		next := m.GetNextChunk()
+		if next == nil {
+			log.Errorf("unexpected message while sending file: %v", m)
+			tun.Out <- net.Err(net.ErrUnexpected)
+			continue
+		}
+
+		credits = min(credits+window(next.GetCredits()), MaxWindow)
+
+		for ; credits > 0 && idx < end; credits-- {
 			log.Infof("[%v] SYNC -> %v : %v/%v", id, cid, idx+1, content.numChunks)
 
-			tun.Out <- &pb.Message{Msg: &pb.Message_Chunk{Chunk: &pb.Chunk{Index: int32(idx), Data: content.chunks[idx]}}}
-			idx++
+			data, err := content.readChunk(blob, idx)
+			if err != nil {
+				tun.Out <- net.Err(net.ErrInternal)
+				return false, err
+			}
 
-			if idx < content.numChunks {
-				continue
+			chunk := &pb.Chunk{Index: int32(idx), Data: data}
+
+			if checksums {
+				hash := sha256.Sum256(data)
+				chunk.Hash = hash[:]
 			}
 
-			log.Infof("[%v] SYNC -> %v : OK", id, cid)
-			return nil
-		}
+			if compression {
+				chunk.Data, chunk.Compressed = compress(data)
+			}
 
-		return ErrClientDisconnected
+			tun.Out <- &pb.Message{Msg: &pb.Message_Chunk{Chunk: chunk}}
+			idx++
+		}
 
-	default:
-		panic("impossible")
+		if idx < end {
+			continue
+		}
 
+		log.Infof("[%v] SYNC -> %v : OK", id, cid)
+		return path != "" || end == content.numChunks, nil
 	}
+
+	return false, ErrClientDisconnected
 }
 
+// syncClip notifies the subscribers and sends the items of the clipboard to every client but srcCid,
+// which gets the listing of items if it agreed on CAPABILITY_ITEMS, followed by an acknowledgement.
+// If one of the other clients consumed the contents srcCid uploaded, srcCid is told so after the
+// acknowledgement.
 func (s *ClipboardService) syncClip(id ClipboardId, srcCid net.CID) {
 	clip := s.getClip(id)
 	r := clip.router
 
-	switch content := clip.content.(type) {
+	clip.mu.Lock()
+	clip.acking[srcCid]++
+	clip.mu.Unlock()
 
-	case ContentText:
-		text := "<empty>"
-		if content.data != "" {
-			text = content.data
+	s.notify(id)
+
+	wg := sync.WaitGroup{}
+	clip.clients.Range(func(key, value any) bool {
+		cid, ok := key.(net.CID)
+		if !ok {
+			panic("impossible")
 		}
-		log.Infof("[%v] SYNC -> @ : TXT %v", id, text)
 
-		r.Broadcast(
-			&pb.Message{Msg: &pb.Message_Text{Text: &pb.Text{Data: content.data}}},
-			map[net.CID]struct{}{srcCid: {}},
-		)
+		client, ok := value.(*Client)
+		if !ok {
+			panic("impossible")
+		}
 
-	case ContentFile:
-		wg := sync.WaitGroup{}
-		for cid := range clip.clients {
-			if cid == srcCid {
-				continue
-			}
+		if cid == srcCid || !client.sync {
+			return true
+		}
 
-			wg.Add(1)
+		wg.Add(1)
 
-			go func() {
-				defer wg.Done()
+		go func() {
+			defer wg.Done()
 
-				err := s.syncClient(id, cid)
-				if err != nil {
-					log.Error(err)
-				}
-			}()
-		}
+			err := s.syncClient(id, cid, nil)
+			if err != nil {
+				log.Error(err)
+			}
+		}()
 
-		wg.Wait()
+		return true
+	})
 
-	default:
-		panic("impossible")
+	wg.Wait()
+
+	clip.mu.Lock()
+	burnt := clip.burnt
+	clip.mu.Unlock()
 
+	if !burnt && s.getClient(id, srcCid).Capable(pb.Capability_CAPABILITY_ITEMS) {
+		err := s.syncClient(id, srcCid, nil)
+		if err != nil {
+			log.Error(err)
+		}
 	}
 
 	err := r.Send(srcCid, &pb.Message{Msg: &pb.Message_Ack{Ack: &pb.Ack{}}})
@@ -300,123 +730,603 @@ func (s *ClipboardService) syncClip(id ClipboardId, srcCid net.CID) {
 	}
 
 	log.Infof("[%v] ACK => %v", id, srcCid)
+
+	clip.mu.Lock()
+	clip.acking[srcCid]--
+
+	item, owed := "", false
+	if clip.acking[srcCid] == 0 {
+		item, owed = clip.owed[srcCid]
+		delete(clip.acking, srcCid)
+		delete(clip.owed, srcCid)
+	}
+	clip.mu.Unlock()
+
+	if owed {
+		s.consumed(id, srcCid, item)
+	}
 }
 
-func (s *ClipboardService) processText(id ClipboardId, cid net.CID, m *pb.Text) {
+func (s *ClipboardService) save(id ClipboardId) {
+	clip := s.getClip(id)
+
+	clip.mu.Lock()
+	history := slices.Clone(clip.history)
+	clip.mu.Unlock()
+
+	err := s.store.Save(id, clip.settings, history)
+	if err != nil {
+		log.Errorf("unable to store %v: %v", id, err)
+	}
+}
+
+func (s *ClipboardService) processHello(id ClipboardId, cid net.CID, m *pb.Hello) {
 	clip := s.getClip(id)
 	r := clip.router
 
-	data := m.GetData()
+	client := s.getClient(id, cid)
+	if client == nil {
+		return
+	}
+
+	log.Infof("[%v] HELLO <= %v : v%v %v", id, cid, m.GetVersion(), m.GetCapabilities())
 
-	text := data
-	if text == "" {
-		text = "<empty>"
+	if m.GetVersion() < MinProtocolVersion {
+		log.Errorf("client %v speaks unsupported protocol version %v", cid, m.GetVersion())
+		r.Send(cid, net.Fatal(ErrProtocolMismatch))
+		client.Cancel()
+		return
 	}
 
-	log.Infof("[%v] $ <= %v : TXT %v", id, cid, text)
+	version := min(m.GetVersion(), ProtocolVersion)
 
-	if file, ok := clip.content.(ContentFile); ok {
-		if !file.ready {
-			log.Error("denied while receiving file")
+	caps := make(map[pb.Capability]struct{})
+	var agreed []pb.Capability
+	for _, capability := range m.GetCapabilities() {
+		if !slices.Contains(capabilities, capability) {
+			continue
+		}
 
-			// TODO: send error here?
+		if _, ok := caps[capability]; ok {
+			continue
+		}
 
-			err := r.Send(cid, &pb.Message{Msg: &pb.Message_Ack{Ack: &pb.Ack{}}})
-			if err != nil {
-				log.Error(err)
-			}
+		caps[capability] = struct{}{}
+		agreed = append(agreed, capability)
+	}
 
-			return
+	client.mu.Lock()
+	client.version = version
+	client.caps = caps
+	if m.GetClientId() != "" {
+		client.id = hashToken(m.GetClientId())
+	}
+	client.mu.Unlock()
+
+	var expires int64
+	if !clip.settings.expires.IsZero() {
+		expires = clip.settings.expires.UnixMilli()
+	}
+
+	err := r.Send(cid, &pb.Message{Msg: &pb.Message_Welcome{Welcome: &pb.Welcome{
+		Version:          version,
+		Capabilities:     agreed,
+		Encrypted:        clip.settings.encrypted,
+		ReadOnly:         client.Role() == RoleViewer,
+		BurnAfterReading: clip.settings.burn,
+		Ttl:              clip.settings.ttl.Milliseconds(),
+		Expires:          expires,
+	}}})
+	if err != nil {
+		log.Error(err)
+	}
+
+	log.Infof("[%v] WELCOME => %v : v%v %v", id, cid, version, agreed)
+
+	client.greet()
+}
+
+// processCancel cancels an upload from a client other than the one sending it, which is the case
+// when the uploader has reconnected.
+func (s *ClipboardService) processCancel(id ClipboardId, cid net.CID, m *pb.Cancel) {
+	clip := s.getClip(id)
+
+	log.Infof("[%v] $ <= %v : CANCEL %v", id, cid, m.GetSession())
+
+	clip.mu.Lock()
+	up := clip.upload
+	clip.mu.Unlock()
+
+	if up == nil || m.GetSession() == "" || m.GetSession() != up.session {
+		log.Errorf("no upload with session %v to cancel", m.GetSession())
+		clip.router.Send(cid, net.Err(net.ErrUnexpected))
+		return
+	}
+
+	s.cancelUpload(id, cid, up)
+}
+
+func (s *ClipboardService) processListVersions(id ClipboardId, cid net.CID) {
+	clip := s.getClip(id)
+
+	log.Infof("[%v] $ <= %v : LIST", id, cid)
+
+	clip.mu.Lock()
+	versions := clip.versions()
+	clip.mu.Unlock()
+
+	err := clip.router.Send(cid, &pb.Message{Msg: &pb.Message_Versions{Versions: versions}})
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+func (s *ClipboardService) processRestoreVersion(id ClipboardId, cid net.CID, m *pb.RestoreVersion) {
+	clip := s.getClip(id)
+	r := clip.router
+
+	log.Infof("[%v] $ <= %v : RESTORE %v", id, cid, m.GetId())
+
+	clip.mu.Lock()
+
+	if clip.upload != nil {
+		busy := clip.busy()
+
+		clip.mu.Unlock()
+
+		log.Error("denied while receiving file")
+
+		err := r.Send(cid, busy)
+		if err != nil {
+			log.Error(err)
+		}
+
+		return
+	}
+
+	version, ok := clip.findVersion(int(m.GetId()))
+	if !ok {
+		clip.mu.Unlock()
+
+		log.Errorf("version %v does not exist", m.GetId())
+		r.Send(cid, net.Err(ErrInvalidVersion))
+		return
+	}
+
+	clip.items = slices.Clone(version.items)
+	evicted := clip.pushVersion(cid)
+
+	clip.mu.Unlock()
+
+	s.save(id)
+
+	s.discard(id, evicted)
+
+	s.syncClip(id, cid)
+
+	if s.getClient(id, cid).Capable(pb.Capability_CAPABILITY_ITEMS) {
+		return
+	}
+
+	err := s.syncClient(id, cid, nil)
+	if err != nil {
+		log.Error(err)
+	}
+}
+
+// processText replaces the items of the clipboard with a text, or pushes it on top of them if add is set.
+func (s *ClipboardService) processText(id ClipboardId, cid net.CID, m *pb.Text, add bool) {
+	clip := s.getClip(id)
+	r := clip.router
+
+	content, err := newText(m, clip.settings.encrypted)
+	if err != nil {
+		log.Errorf("invalid text from %v: %v", cid, err)
+		r.Send(cid, net.Err(err))
+		return
+	}
+
+	log.Infof("[%v] $ <= %v : TXT %v", id, cid, content)
+
+	clip.mu.Lock()
+
+	if clip.upload != nil {
+		busy := clip.busy()
+
+		clip.mu.Unlock()
+
+		log.Error("denied while receiving file")
+
+		err := r.Send(cid, busy)
+		if err != nil {
+			log.Error(err)
 		}
+
+		return
+	}
+
+	item := newItem(content, cid, s.getClient(id, cid).uploader())
+	if add {
+		clip.items = append(slices.Clone(clip.items), item)
+	} else {
+		clip.items = []Item{item}
 	}
 
-	clip.content = ContentText{data}
+	evicted := clip.pushVersion(cid)
+
+	clip.mu.Unlock()
+
+	s.save(id)
+
+	s.discard(id, evicted)
+
 	s.syncClip(id, cid)
 }
 
-func (s *ClipboardService) processFile(id ClipboardId, timer *time.Timer, cid net.CID, m *pb.FileHeader) {
+// processFile starts receiving a file, or resumes the upload with the same session. Like processText,
+// the file replaces the items of the clipboard unless add is set.
+func (s *ClipboardService) processFile(id ClipboardId, cid net.CID, m *pb.FileHeader, add bool) {
 	clip := s.getClip(id)
 	r := clip.router
 
 	log.Infof("[%v] $ <= %v : FILE %v/%v", id, cid, m.GetFilename(), m.GetNumChunks())
 
-	if file, ok := clip.content.(ContentFile); ok {
-		if !file.ready {
-			log.Error("denied while receiving file")
+	clip.mu.Lock()
+
+	if up := clip.upload; up != nil {
+		if m.GetSession() == "" || m.GetSession() != up.session {
+			busy := clip.busy()
 
-			// TODO: also send error?
+			clip.mu.Unlock()
+
+			log.Error("denied while receiving file")
 
-			err := r.Send(cid, &pb.Message{Msg: &pb.Message_Ack{Ack: &pb.Ack{}}})
+			err := r.Send(cid, busy)
 			if err != nil {
 				log.Error(err)
 			}
 
 			return
 		}
+
+		log.Infof("[%v] $ <= %v : RESUME %v", id, cid, up.session)
+
+		prev := up.resume(cid)
+
+		clip.mu.Unlock()
+
+		if prev != nil {
+			prev.Cancel()
+		}
+
+		s.receiveFile(id, cid, up, s.uploadWindow(id, cid, m))
+		return
+	}
+
+	if !validFilename(m.GetFilename()) {
+		clip.mu.Unlock()
+
+		log.Errorf("invalid filename %q", m.GetFilename())
+		r.Send(cid, net.Err(ErrInvalidPath))
+		return
+	}
+
+	envelope, err := newEnvelope(m.GetEnvelope(), clip.settings.encrypted)
+	if err == nil && envelope != nil && m.GetManifest() != nil {
+		err = ErrEncryptedTree
 	}
+	if err != nil {
+		clip.mu.Unlock()
 
-	originalContent := clip.content
+		log.Errorf("invalid envelope for %v: %v", m.GetFilename(), err)
+		r.Send(cid, net.Err(err))
+		return
+	}
 
-	clip.content = ContentFile{
+	var entries []Entry
+	if m.GetManifest() != nil {
+		entries, err = newEntries(m.GetManifest())
+		if err != nil {
+			clip.mu.Unlock()
+
+			log.Errorf("invalid manifest for %v", m.GetFilename())
+			r.Send(cid, net.Err(err))
+			return
+		}
+	}
+
+	blob, blobName, err := s.createBlob(id)
+	if err != nil {
+		clip.mu.Unlock()
+
+		log.Error(err)
+		r.Send(cid, net.Err(net.ErrInternal))
+		return
+	}
+
+	up := &upload{
+		session:  m.GetSession(),
+		cid:      cid,
+		uploader: s.getClient(id, cid).uploader(),
+		blob:     blob,
+		blobName: blobName,
+		hash:     sha256.New(),
+		digest:   m.GetDigest(),
+		add:      add,
+	}
+
+	session := m.GetSession()
+	if session == "" {
+		session = blobName
+	}
+
+	up.file = ContentFile{
+		session:     session,
+		blob:        blobName,
+		offsets:     []int64{0},
 		filename:    m.GetFilename(),
 		contentType: m.GetContentType(),
 		numChunks:   int(m.GetNumChunks()),
+		entries:     entries,
+		envelope:    envelope,
 	}
 
+	clip.upload = up
+
+	clip.mu.Unlock()
+
+	s.receiveFile(id, cid, up, s.uploadWindow(id, cid, m))
+}
+
+// uploadWindow returns the window announced in the header, clients which agreed on windowing
+// during the handshake and did not announce one get the largest window.
+func (s *ClipboardService) uploadWindow(id ClipboardId, cid net.CID, m *pb.FileHeader) int {
+	if m.GetWindow() == 0 && s.getClient(id, cid).Capable(pb.Capability_CAPABILITY_WINDOWING) {
+		return MaxWindow
+	}
+
+	return window(m.GetWindow())
+}
+
+// window returns the number of chunks a peer may have in flight. Peers that do not announce
+// a window transfer one chunk at a time.
+func window(n int32) int {
+	return max(min(int(n), MaxWindow), 1)
+}
+
+// receiveFile receives the chunks of up from cid, letting the uploader send up to window chunks
+// ahead of the one the server is waiting for.
+func (s *ClipboardService) receiveFile(id ClipboardId, cid net.CID, up *upload, window int) {
+	clip := s.getClip(id)
+	r := clip.router
+
+	up.Lock()
+	defer up.Unlock()
+
 	tun, err := r.Tunnel(cid)
 	if err != nil {
 		log.Error(err)
+		s.detachUpload(id, cid, up)
 		return
 	}
 	defer tun.Cancel()
 
-	tun.Out <- &pb.Message{Msg: &pb.Message_NextChunk{NextChunk: &pb.NextChunk{}}}
+	clip.mu.Lock()
+
+	if clip.upload != up || up.cid != cid {
+		clip.mu.Unlock()
+
+		log.Errorf("upload session %v is gone", up.session)
+		tun.Out <- net.Err(ErrUploadAborted)
+		return
+	}
+
+	up.tun = tun
+	file := up.file
+
+	clip.mu.Unlock()
+
+	abort := func() {
+		clip.mu.Lock()
+		defer clip.mu.Unlock()
+
+		s.abortUpload(id, up)
+	}
+
+	if file.nextChunkIndex == file.numChunks {
+		err := s.completeUpload(id, cid, up, file)
+		if err != nil {
+			tun.Out <- net.Err(err)
+		}
+
+		return
+	}
+
+	tun.Out <- &pb.Message{Msg: &pb.Message_NextChunk{NextChunk: &pb.NextChunk{
+		Index:   int32(file.nextChunkIndex),
+		Credits: int32(window),
+	}}}
+
+	// credits are only granted for chunks still to come, as those granted after the last one
+	// could reach the uploader after the acknowledgement and be taken for the next upload
+	granted := file.nextChunkIndex + window
 
 	for m := range tun.In {
-		timer.Reset(ClipDeadline) // FIXME
+		if m.GetCancel() != nil {
+			log.Infof("[%v] $ <= %v : CANCEL", id, cid)
+			s.cancelUpload(id, cid, up)
+			return
+		}
 
 		chunk := m.GetChunk()
 		if chunk == nil {
 			log.Errorf("unexpected message while receiving file")
-			tun.Out <- net.Err(fmt.Errorf("unexpected message"))
-			return
-		}
-
-		file, ok := clip.content.(ContentFile)
-		if !ok {
-			log.Errorf("unexpected state of contents: %v", clip.content)
-			tun.Out <- net.Err(fmt.Errorf("internal server error"))
+			tun.Out <- net.Err(net.ErrUnexpected)
+			abort()
 			return
 		}
 
 		if int(chunk.GetIndex()) != file.nextChunkIndex {
 			log.Errorf("received chunk with index %v, but expected %v", chunk.GetIndex(), file.nextChunkIndex)
-			tun.Out <- net.Err(fmt.Errorf("transmission disordered"))
+			tun.Out <- net.Err(net.ErrDisordered)
+			abort()
 			return
 		}
 
 		log.Infof("[%v] <- %v : %v/%v", id, cid, chunk.GetIndex()+1, file.numChunks)
 
+		data := chunk.GetData()
+		if chunk.GetCompressed() {
+			var err error
+			data, err = decompress(data)
+			if err != nil {
+				log.Error(err)
+
+				if !errors.Is(err, ErrChunkTooLarge) {
+					err = ErrInvalidChunk
+				}
+
+				tun.Out <- net.Err(err)
+				abort()
+				return
+			}
+		}
+
+		if len(data) > MaxChunkSize {
+			log.Errorf("received chunk of %v bytes", len(data))
+			tun.Out <- net.Err(ErrChunkTooLarge)
+			abort()
+			return
+		}
+
+		if file.envelope != nil && len(data) < SealOverhead {
+			log.Errorf("received sealed chunk of %v bytes", len(data))
+			tun.Out <- net.Err(ErrInvalidChunk)
+			abort()
+			return
+		}
+
+		if len(file.entries) != 0 {
+			offset := file.offsets[len(file.offsets)-1]
+
+			end, ok := file.boundary(offset)
+			if !ok || offset+int64(len(data)) > end {
+				log.Errorf("chunk %v does not fit in the files of the manifest", chunk.GetIndex())
+				tun.Out <- net.Err(ErrInvalidManifest)
+				abort()
+				return
+			}
+		}
+
+		if hash := chunk.GetHash(); len(hash) != 0 {
+			sum := sha256.Sum256(data)
+			if !bytes.Equal(sum[:], hash) {
+				log.Errorf("checksum of chunk %v does not match", chunk.GetIndex())
+				tun.Out <- net.Err(ErrChecksumMismatch)
+				s.detachUpload(id, cid, up)
+				return
+			}
+		}
+
+		_, err := up.blob.Write(data)
+		if err != nil {
+			log.Error(err)
+			tun.Out <- net.Err(net.ErrInternal)
+			abort()
+			return
+		}
+
+		up.hash.Write(data)
+
 		file.nextChunkIndex++
-		file.chunks = append(file.chunks, chunk.GetData())
+		file.offsets = append(file.offsets, file.offsets[len(file.offsets)-1]+int64(len(data)))
 
 		if file.nextChunkIndex < file.numChunks {
-			clip.content = file
-			tun.Out <- &pb.Message{Msg: &pb.Message_NextChunk{NextChunk: &pb.NextChunk{}}}
+			clip.mu.Lock()
+			up.file = file
+			clip.mu.Unlock()
+
+			if granted < file.numChunks {
+				granted++
+
+				tun.Out <- &pb.Message{Msg: &pb.Message_NextChunk{NextChunk: &pb.NextChunk{
+					Index:   int32(file.nextChunkIndex),
+					Credits: 1,
+				}}}
+			}
 			continue
 		}
 
-		file.ready = true
-		clip.content = file
-
-		log.Infof("[%v] <- %v : OK", id, cid)
-		s.syncClip(id, cid)
+		err = s.completeUpload(id, cid, up, file)
+		if err != nil {
+			tun.Out <- net.Err(err)
+		}
 
 		return
 	}
 
-	clip.content = originalContent
 	log.Error("client disconnected while receiving file")
+	s.detachUpload(id, cid, up)
+}
+
+// completeUpload stores the file once all of its chunks were received and syncs it to the other clients.
+func (s *ClipboardService) completeUpload(id ClipboardId, cid net.CID, up *upload, file ContentFile) error {
+	clip := s.getClip(id)
+
+	abort := func() {
+		clip.mu.Lock()
+		defer clip.mu.Unlock()
+
+		s.abortUpload(id, up)
+	}
+
+	if len(file.entries) != 0 && file.offsets[len(file.offsets)-1] != file.size() {
+		log.Errorf("files of %v are incomplete", file.filename)
+		abort()
+		return ErrInvalidManifest
+	}
+
+	file.digest = up.hash.Sum(nil)
+	if len(up.digest) != 0 && !bytes.Equal(file.digest, up.digest) {
+		log.Errorf("checksum of file %v does not match", file.filename)
+		abort()
+		return ErrChecksumMismatch
+	}
+
+	err := up.blob.Sync()
+	if err != nil {
+		log.Error(err)
+		abort()
+		return net.ErrInternal
+	}
+
+	err = up.blob.Close()
+	if err != nil {
+		log.Error(err)
+	}
+
+	file.ready = true
+
+	item := newItem(file, cid, up.uploader)
+
+	clip.mu.Lock()
+	clip.upload = nil
+	if up.add {
+		clip.items = append(slices.Clone(clip.items), item)
+	} else {
+		clip.items = []Item{item}
+	}
+	evicted := clip.pushVersion(cid)
+	clip.mu.Unlock()
+
+	log.Infof("[%v] <- %v : OK", id, cid)
+	s.save(id)
+
+	s.discard(id, evicted)
+
+	s.syncClip(id, cid)
+
+	return nil
 }
 
 func (s *ClipboardService) Start(id ClipboardId) {
@@ -427,34 +1337,83 @@ func (s *ClipboardService) Start(id ClipboardId) {
 
 	log.Infof("* START %v", id)
 
-	timer := time.NewTimer(ClipDeadline)
-	go func() {
-		select {
-
-		case <-timer.C:
-			log.Error("clip deadline expired")
+	go s.expire(id)
 
-		case <-clip.ctx.Done():
+	for m := range r.Drain {
 
+		if m.GetHello() == nil {
+			s.getClient(id, m.Cid).greet()
 		}
 
-		clip.cancel()
-	}()
-
-	for m := range r.Drain {
-		timer.Reset(ClipDeadline)
+		if changes(m.Message) && s.getClient(id, m.Cid).Role() != RoleEditor {
+			log.Errorf("viewer %v tried to change the clipboard", m.Cid)
+			r.Send(m.Cid, net.Err(ErrReadOnly))
+			continue
+		}
 
 		if text := m.GetText(); text != nil {
-			s.processText(id, m.Cid, text)
+			s.processText(id, m.Cid, text, false)
 			continue
 		}
 
 		if hdr := m.GetHdr(); hdr != nil {
-			go s.processFile(id, timer, m.Cid, hdr)
+			go s.processFile(id, m.Cid, hdr, false)
+			continue
+		}
+
+		if add := m.GetAddItem(); add != nil {
+			if text := add.GetText(); text != nil {
+				s.processText(id, m.Cid, text, true)
+				continue
+			}
+
+			if hdr := add.GetHdr(); hdr != nil {
+				go s.processFile(id, m.Cid, hdr, true)
+				continue
+			}
+		}
+
+		if remove := m.GetRemoveItem(); remove != nil {
+			go s.processRemoveItem(id, m.Cid, remove)
+			continue
+		}
+
+		if move := m.GetMoveItem(); move != nil {
+			go s.processMoveItem(id, m.Cid, move)
+			continue
+		}
+
+		if hello := m.GetHello(); hello != nil {
+			s.processHello(id, m.Cid, hello)
+			continue
+		}
+
+		if cancel := m.GetCancel(); cancel != nil {
+			s.processCancel(id, m.Cid, cancel)
+			continue
+		}
+
+		if m.GetListVersions() != nil {
+			s.processListVersions(id, m.Cid)
+			continue
+		}
+
+		if restore := m.GetRestoreVersion(); restore != nil {
+			go s.processRestoreVersion(id, m.Cid, restore)
+			continue
+		}
+
+		if fetch := m.GetFetch(); fetch != nil {
+			go func() {
+				err := s.syncClient(id, m.Cid, fetch)
+				if err != nil {
+					log.Error(err)
+				}
+			}()
 			continue
 		}
 
 		log.Errorf("unexpected message")
-		r.Send(m.Cid, net.Err(fmt.Errorf("unpexpected message")))
+		r.Send(m.Cid, net.Err(net.ErrUnexpected))
 	}
 }
@@ -0,0 +1,57 @@
+package clipservice
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+)
+
+// MaxChunkSize bounds the size of a decompressed chunk.
+const MaxChunkSize = 4 << 20
+
+var ErrChunkTooLarge = net.NewError(pb.ErrorCode_ERROR_CODE_TOO_LARGE, "chunk is too large")
+
+// compress deflates data, returning it unchanged if it does not get any smaller.
+func compress(data []byte) ([]byte, bool) {
+	buf := bytes.Buffer{}
+
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = w.Write(data)
+	if err != nil {
+		panic(err)
+	}
+
+	err = w.Close()
+	if err != nil {
+		panic(err)
+	}
+
+	if buf.Len() >= len(data) {
+		return data, false
+	}
+
+	return buf.Bytes(), true
+}
+
+func decompress(data []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+
+	buf, err := io.ReadAll(io.LimitReader(r, MaxChunkSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(buf) > MaxChunkSize {
+		return nil, ErrChunkTooLarge
+	}
+
+	return buf, nil
+}
@@ -0,0 +1,123 @@
+package clipservice
+
+import (
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+)
+
+var ErrTreeDownload = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_PATH, "item is a tree, a path is required")
+
+// Download is an item of a clipboard opened for reading over plain HTTP. Files are read from their
+// blob, where the chunks lie one after another, so any range of bytes can be served.
+type Download struct {
+	*io.SectionReader
+
+	Filename    string // empty for text
+	ContentType string
+	Modified    time.Time
+
+	blob *os.File
+}
+
+// Download opens an item of the clipboard, the top one if item is empty. A path selects one of the
+// files of a tree.
+func (s *ClipboardService) Download(id ClipboardId, item string, p string) (*Download, error) {
+	clip := s.getClip(id)
+	if clip == nil {
+		return nil, ErrInvalidClipId
+	}
+
+	if clip.settings.encrypted {
+		return nil, ErrSealed
+	}
+
+	if clip.settings.burn {
+		return nil, ErrBurnAfterReading
+	}
+
+	clip.mu.Lock()
+	defer clip.mu.Unlock()
+
+	selected := topItem(clip.items)
+	if item != "" {
+		idx, ok := clip.findItem(item)
+		if !ok {
+			return nil, ErrInvalidItem
+		}
+
+		selected = clip.items[idx]
+	}
+
+	switch content := selected.content.(type) {
+
+	case ContentText:
+		if p != "" {
+			return nil, ErrInvalidPath
+		}
+
+		return &Download{
+			SectionReader: io.NewSectionReader(strings.NewReader(content.data), 0, int64(len(content.data))),
+			ContentType:   "text/plain; charset=utf-8",
+			Modified:      selected.created,
+		}, nil
+
+	case ContentFile:
+		d := &Download{
+			Filename:    fileName(content.filename),
+			ContentType: content.contentType,
+			Modified:    selected.created,
+		}
+
+		offset, size := int64(0), content.offsets[len(content.offsets)-1]
+
+		if len(content.entries) != 0 {
+			if p == "" {
+				return nil, ErrTreeDownload
+			}
+
+			entry, ok := content.findEntry(p)
+			if !ok || entry.dir {
+				return nil, ErrInvalidPath
+			}
+
+			offset, size = entry.offset, entry.size
+			d.Filename = path.Base(entry.path)
+			d.ContentType = mime.TypeByExtension(path.Ext(entry.path))
+		} else if p != "" {
+			return nil, ErrInvalidPath
+		}
+
+		if d.ContentType == "" {
+			d.ContentType = "application/octet-stream"
+		}
+
+		blob, err := s.openBlob(id, content.blob)
+		if err != nil {
+			return nil, err
+		}
+
+		d.blob = blob
+		d.SectionReader = io.NewSectionReader(blob, offset, size)
+
+		return d, nil
+
+	default:
+		panic("impossible")
+
+	}
+}
+
+func (d *Download) Close() error {
+	if d.blob == nil {
+		return nil
+	}
+
+	return d.blob.Close()
+}
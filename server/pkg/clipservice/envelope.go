@@ -0,0 +1,161 @@
+package clipservice
+
+import (
+	"fmt"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+)
+
+const (
+	SealOverhead    = 16 // size of the authentication tag appended by the supported ciphers
+	MaxSaltSize     = 64
+	MaxIterations   = 10_000_000
+	MaxMetadataSize = 4 << 10
+)
+
+// nonceSizes are the nonce sizes of the supported ciphers.
+var nonceSizes = map[pb.Cipher]int{
+	pb.Cipher_CIPHER_AES_256_GCM:        12,
+	pb.Cipher_CIPHER_XCHACHA20_POLY1305: 24,
+}
+
+// Envelope tells how the content of an encrypted clipboard was sealed. The server has no key to open it,
+// it only checks that the envelope is well formed and passes it on to the clients.
+type Envelope struct {
+	cipher     pb.Cipher
+	nonce      []byte
+	kdf        pb.KdfAlgorithm
+	salt       []byte
+	iterations uint32
+	metadata   []byte // sealed metadata of a file
+}
+
+var (
+	ErrInvalidEnvelope    = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_ENVELOPE, "invalid envelope")
+	ErrEnvelopeRequired   = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_ENVELOPE, "clipboard is encrypted")
+	ErrUnexpectedEnvelope = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_ENVELOPE, "clipboard is not encrypted")
+	ErrEncryptedTree      = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_MANIFEST, "encrypted clipboards do not hold trees")
+	ErrEncryptionRequired = net.NewError(pb.ErrorCode_ERROR_CODE_ENCRYPTION_REQUIRED, "clipboard is encrypted")
+	ErrSealed             = net.NewError(pb.ErrorCode_ERROR_CODE_ENCRYPTION_REQUIRED, "contents of encrypted clipboards are only sent to clients")
+)
+
+// newEnvelope checks m against the setting of the clipboard, contents of encrypted clipboards must come
+// in an envelope and contents of other clipboards must not. A nil envelope is returned for the latter.
+func newEnvelope(m *pb.Envelope, encrypted bool) (*Envelope, error) {
+	if m == nil {
+		if encrypted {
+			return nil, ErrEnvelopeRequired
+		}
+
+		return nil, nil
+	}
+
+	if !encrypted {
+		return nil, ErrUnexpectedEnvelope
+	}
+
+	size, ok := nonceSizes[m.GetCipher()]
+	if !ok || len(m.GetNonce()) != size {
+		return nil, ErrInvalidEnvelope
+	}
+
+	kdf := m.GetKdf()
+	salt, iterations := kdf.GetSalt(), kdf.GetIterations()
+
+	switch kdf.GetAlgorithm() {
+
+	case pb.KdfAlgorithm_KDF_ALGORITHM_NONE:
+		if len(salt) != 0 || iterations != 0 {
+			return nil, ErrInvalidEnvelope
+		}
+
+	case pb.KdfAlgorithm_KDF_ALGORITHM_HKDF_SHA256:
+		if len(salt) == 0 || len(salt) > MaxSaltSize || iterations != 0 {
+			return nil, ErrInvalidEnvelope
+		}
+
+	case pb.KdfAlgorithm_KDF_ALGORITHM_PBKDF2_SHA256:
+		if len(salt) == 0 || len(salt) > MaxSaltSize || iterations == 0 || iterations > MaxIterations {
+			return nil, ErrInvalidEnvelope
+		}
+
+	default:
+		return nil, ErrInvalidEnvelope
+
+	}
+
+	metadata := m.GetMetadata()
+	if len(metadata) != 0 && (len(metadata) < SealOverhead || len(metadata) > MaxMetadataSize+SealOverhead) {
+		return nil, ErrInvalidEnvelope
+	}
+
+	return &Envelope{
+		cipher:     m.GetCipher(),
+		nonce:      m.GetNonce(),
+		kdf:        kdf.GetAlgorithm(),
+		salt:       salt,
+		iterations: iterations,
+		metadata:   metadata,
+	}, nil
+}
+
+func (e *Envelope) message() *pb.Envelope {
+	if e == nil {
+		return nil
+	}
+
+	return &pb.Envelope{
+		Cipher:   e.cipher,
+		Nonce:    e.nonce,
+		Kdf:      &pb.Kdf{Algorithm: e.kdf, Salt: e.salt, Iterations: e.iterations},
+		Metadata: e.metadata,
+	}
+}
+
+// newText checks a text sent to a clipboard, which is sealed in its ciphertext if the clipboard is encrypted.
+func newText(m *pb.Text, encrypted bool) (ContentText, error) {
+	envelope, err := newEnvelope(m.GetEnvelope(), encrypted)
+	if err != nil {
+		return ContentText{}, err
+	}
+
+	if envelope == nil {
+		if len(m.GetCiphertext()) != 0 {
+			return ContentText{}, ErrUnexpectedEnvelope
+		}
+
+		return ContentText{data: m.GetData()}, nil
+	}
+
+	ciphertext := m.GetCiphertext()
+	if m.GetData() != "" || len(ciphertext) < SealOverhead || len(ciphertext) > MaxTextSize+SealOverhead {
+		return ContentText{}, ErrInvalidEnvelope
+	}
+
+	return ContentText{data: string(ciphertext), envelope: envelope}, nil
+}
+
+func (t ContentText) message(item string) *pb.Text {
+	if t.envelope != nil {
+		return &pb.Text{Ciphertext: []byte(t.data), Envelope: t.envelope.message(), Item: item}
+	}
+
+	return &pb.Text{Data: t.data, Item: item}
+}
+
+// String describes the text for the logs.
+func (t ContentText) String() string {
+	switch {
+
+	case t.envelope != nil:
+		return fmt.Sprintf("<sealed %v bytes>", len(t.data))
+
+	case t.data == "":
+		return "<empty>"
+
+	default:
+		return t.data
+
+	}
+}
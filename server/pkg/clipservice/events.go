@@ -0,0 +1,109 @@
+package clipservice
+
+import (
+	"context"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+)
+
+// Subscriber is a read-only connection to a clipboard. Unlike clients, subscribers are not sent
+// the contents of the clipboard, but an event describing its items each time they change, starting
+// with the current ones.
+type Subscriber struct {
+	context.Context
+	Cancel context.CancelFunc
+
+	Cid net.CID
+
+	Out <-chan net.OutMessage
+}
+
+func (s *ClipboardService) Subscribe(id ClipboardId, ctx context.Context) (*Subscriber, error) {
+	clip := s.getClip(id)
+	if clip == nil {
+		return nil, ErrInvalidClipId
+	}
+
+	if clip.settings.burn {
+		return nil, ErrBurnAfterReading
+	}
+
+	subCtx, subCancel := context.WithCancel(ctx)
+
+	// out is never closed, as the router may still be broadcasting to it
+	out := make(chan net.OutMessage, 15)
+
+	clip.mu.Lock()
+	out <- clip.event()
+	clip.mu.Unlock()
+
+	cid := clip.router.Connect(out, subCtx)
+	clip.join()
+	log.Infof("[%v] + %v (events)", id, cid)
+
+	go func() {
+		select {
+		case <-subCtx.Done():
+		case <-clip.ctx.Done():
+		}
+
+		subCancel()
+		clip.leave()
+		log.Infof("[%v] - %v (events)", id, cid)
+	}()
+
+	return &Subscriber{
+		Context: subCtx,
+		Cancel:  subCancel,
+		Cid:     cid,
+		Out:     out,
+	}, nil
+}
+
+// event must be called with clip.mu held.
+func (clip *Clipboard) event() net.OutMessage {
+	m := &pb.Event{}
+
+	if len(clip.history) != 0 {
+		v := clip.history[len(clip.history)-1]
+
+		m.Version = int32(v.id)
+		m.Author = v.author.String()
+		m.Timestamp = v.created.UnixMilli()
+	}
+
+	for _, item := range clip.items {
+		m.Items = append(m.Items, itemMessage(item))
+	}
+
+	return &pb.Message{Msg: &pb.Message_Event{Event: m}}
+}
+
+// notify broadcasts the current items to the subscribers, which are the connections of the router
+// that are not clients.
+func (s *ClipboardService) notify(id ClipboardId) {
+	clip := s.getClip(id)
+
+	clip.mu.Lock()
+	event := clip.event()
+	clip.mu.Unlock()
+
+	clients := make(map[net.CID]struct{})
+	clip.clients.Range(func(key, _ any) bool {
+		cid, ok := key.(net.CID)
+		if !ok {
+			panic("impossible")
+		}
+
+		clients[cid] = struct{}{}
+
+		return true
+	})
+
+	log.Infof("[%v] EVENT -> @ : v%v", id, event.GetEvent().GetVersion())
+
+	clip.router.Broadcast(event, clients)
+}
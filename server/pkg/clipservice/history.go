@@ -0,0 +1,129 @@
+package clipservice
+
+import (
+	"slices"
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+)
+
+const (
+	HistoryLimit   = 10
+	VersionPreview = 256 // number of characters of text shown when listing versions
+)
+
+// Version is a state of the items the clipboard has held. The last version of the history is the current state.
+type Version struct {
+	id      int
+	items   []Item
+	author  net.CID
+	created time.Time
+}
+
+// pushVersion records the current items as a new version and returns the contents which fell out
+// of the history. Must be called with clip.mu held.
+func (clip *Clipboard) pushVersion(author net.CID) []Content {
+	id := 1
+	if len(clip.history) != 0 {
+		id = clip.history[len(clip.history)-1].id + 1
+	}
+
+	clip.history = append(clip.history, Version{
+		id:      id,
+		items:   slices.Clone(clip.items),
+		author:  author,
+		created: time.Now(),
+	})
+
+	var evicted []Content
+	for len(clip.history) > HistoryLimit {
+		for _, item := range clip.history[0].items {
+			evicted = append(evicted, item.content)
+		}
+		clip.history = clip.history[1:]
+	}
+
+	return evicted
+}
+
+// findVersion must be called with clip.mu held.
+func (clip *Clipboard) findVersion(id int) (Version, bool) {
+	for _, v := range clip.history {
+		if v.id == id {
+			return v, true
+		}
+	}
+
+	return Version{}, false
+}
+
+// referenced reports whether a blob is still used by the clipboard. Must be called with clip.mu held.
+func (clip *Clipboard) referenced(blob string) bool {
+	items := slices.Clone(clip.items)
+	for _, v := range clip.history {
+		items = append(items, v.items...)
+	}
+
+	for _, item := range items {
+		if file, ok := item.content.(ContentFile); ok && file.blob == blob {
+			return true
+		}
+	}
+
+	return false
+}
+
+// versions must be called with clip.mu held. The items of burn after reading clipboards are left out,
+// as listing them does not count as reading them.
+func (clip *Clipboard) versions() *pb.Versions {
+	m := &pb.Versions{}
+
+	for _, v := range clip.history {
+		version := &pb.Version{
+			Id:        int32(v.id),
+			Timestamp: v.created.UnixMilli(),
+			Author:    v.author.String(),
+		}
+
+		items := v.items
+		if clip.settings.burn {
+			items = nil
+		}
+
+		for _, item := range items {
+			version.Items = append(version.Items, previewItem(item))
+		}
+
+		switch content := previewItem(topItem(items)).GetContent().(type) {
+
+		case *pb.Item_Text:
+			version.Content = &pb.Version_Text{Text: content.Text}
+
+		case *pb.Item_Hdr:
+			version.Content = &pb.Version_Hdr{Hdr: content.Hdr}
+
+		default:
+			panic("impossible")
+
+		}
+
+		m.Versions = append(m.Versions, version)
+		m.Current = int32(v.id)
+	}
+
+	return m
+}
+
+// previewItem describes an item in a listing of versions, with its text cut to VersionPreview characters.
+// Sealed texts are left whole, as clients could not open them otherwise.
+func previewItem(item Item) *pb.Item {
+	if content, ok := item.content.(ContentText); ok && content.envelope == nil {
+		text := []rune(content.data)
+		if len(text) > VersionPreview {
+			item.content = ContentText{data: string(text[:VersionPreview])}
+		}
+	}
+
+	return itemMessage(item)
+}
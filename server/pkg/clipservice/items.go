@@ -0,0 +1,165 @@
+package clipservice
+
+import (
+	"slices"
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+)
+
+// Item is an entry of a clipboard. Clients that did not agree on CAPABILITY_ITEMS only see the top
+// of the stack, which is the last item.
+type Item struct {
+	id       string
+	content  Content
+	author   net.CID
+	uploader []byte // see Client.uploader
+	created  time.Time
+}
+
+func newItemId() string {
+	return uuid.NewString()[:8]
+}
+
+func newItem(content Content, author net.CID, uploader []byte) Item {
+	return Item{id: newItemId(), content: content, author: author, uploader: uploader, created: time.Now()}
+}
+
+// topItem returns the last of items, an empty clipboard holds an empty text.
+func topItem(items []Item) Item {
+	if len(items) == 0 {
+		return Item{content: ContentText{}}
+	}
+
+	return items[len(items)-1]
+}
+
+// findItem must be called with clip.mu held.
+func (clip *Clipboard) findItem(id string) (int, bool) {
+	idx := slices.IndexFunc(clip.items, func(item Item) bool { return item.id == id })
+	return idx, idx != -1
+}
+
+func itemMessage(item Item) *pb.Item {
+	m := &pb.Item{Id: item.id}
+
+	switch content := item.content.(type) {
+
+	case ContentText:
+		m.Content = &pb.Item_Text{Text: content.message(item.id)}
+
+	case ContentFile:
+		m.Content = &pb.Item_Hdr{Hdr: content.header(item.id)}
+
+	default:
+		panic("impossible")
+
+	}
+
+	return m
+}
+
+// itemsMessage must be called with clip.mu held.
+func (clip *Clipboard) itemsMessage() net.OutMessage {
+	m := &pb.Items{}
+	for _, item := range clip.items {
+		m.Items = append(m.Items, itemMessage(item))
+	}
+
+	return &pb.Message{Msg: &pb.Message_Items{Items: m}}
+}
+
+func (s *ClipboardService) processRemoveItem(id ClipboardId, cid net.CID, m *pb.RemoveItem) {
+	clip := s.getClip(id)
+
+	log.Infof("[%v] $ <= %v : REMOVE %v", id, cid, m.GetId())
+
+	clip.mu.Lock()
+
+	if clip.upload != nil {
+		busy := clip.busy()
+
+		clip.mu.Unlock()
+
+		log.Error("denied while receiving file")
+
+		err := clip.router.Send(cid, busy)
+		if err != nil {
+			log.Error(err)
+		}
+
+		return
+	}
+
+	idx, ok := clip.findItem(m.GetId())
+	if !ok {
+		clip.mu.Unlock()
+
+		log.Errorf("item %v does not exist", m.GetId())
+		clip.router.Send(cid, net.Err(ErrInvalidItem))
+		return
+	}
+
+	clip.items = slices.Delete(slices.Clone(clip.items), idx, idx+1)
+	evicted := clip.pushVersion(cid)
+
+	clip.mu.Unlock()
+
+	s.save(id)
+
+	s.discard(id, evicted)
+
+	s.syncClip(id, cid)
+}
+
+func (s *ClipboardService) processMoveItem(id ClipboardId, cid net.CID, m *pb.MoveItem) {
+	clip := s.getClip(id)
+
+	log.Infof("[%v] $ <= %v : MOVE %v -> %v", id, cid, m.GetId(), m.GetPosition())
+
+	clip.mu.Lock()
+
+	if clip.upload != nil {
+		busy := clip.busy()
+
+		clip.mu.Unlock()
+
+		log.Error("denied while receiving file")
+
+		err := clip.router.Send(cid, busy)
+		if err != nil {
+			log.Error(err)
+		}
+
+		return
+	}
+
+	idx, ok := clip.findItem(m.GetId())
+	if !ok {
+		clip.mu.Unlock()
+
+		log.Errorf("item %v does not exist", m.GetId())
+		clip.router.Send(cid, net.Err(ErrInvalidItem))
+		return
+	}
+
+	item := clip.items[idx]
+	items := slices.Delete(slices.Clone(clip.items), idx, idx+1)
+
+	position := max(min(int(m.GetPosition()), len(items)), 0)
+	clip.items = slices.Insert(items, position, item)
+
+	evicted := clip.pushVersion(cid)
+
+	clip.mu.Unlock()
+
+	s.save(id)
+
+	s.discard(id, evicted)
+
+	s.syncClip(id, cid)
+}
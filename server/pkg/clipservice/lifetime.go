@@ -0,0 +1,228 @@
+package clipservice
+
+import (
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+)
+
+const ExpiryWarning = time.Second * 30 // how long before a clipboard ends its clients are warned
+
+// Policy bounds the lifetimes creators may choose for their clipboards, and how long clients have
+// to say hello before they are taken for legacy clients.
+type Policy struct {
+	DefaultTTL    time.Duration
+	MinTTL        time.Duration
+	MaxTTL        time.Duration
+	MaxLifetime   time.Duration // latest expiry after the creation of a clipboard, 0 for no limit
+	HelloDeadline time.Duration
+}
+
+var DefaultPolicy = Policy{
+	DefaultTTL:    ClipDeadline,
+	MinTTL:        time.Second * 30,
+	MaxTTL:        time.Hour * 24,
+	MaxLifetime:   time.Hour * 24 * 30,
+	HelloDeadline: HelloDeadline,
+}
+
+// Lifetime tells when a clipboard ends.
+type Lifetime struct {
+	TTL     time.Duration // how long the clipboard lives on once the last client has left
+	Expires time.Time     // zero for never
+	Present int           // number of connected clients and subscribers
+	End     time.Time     // when the clipboard ends if nobody connects, zero while somebody is connected and it does not expire
+}
+
+var ErrInvalidLifetime = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_LIFETIME, "lifetime is out of the bounds set by the server")
+
+// lifetime checks the TTL and the expiry chosen for a new clipboard, a zero TTL picks the default one.
+func (p Policy) lifetime(ttl time.Duration, expires time.Time) (time.Duration, time.Time, error) {
+	if ttl == 0 {
+		ttl = p.DefaultTTL
+	}
+
+	if ttl < p.MinTTL || ttl > p.MaxTTL {
+		return 0, time.Time{}, ErrInvalidLifetime
+	}
+
+	if expires.IsZero() {
+		return ttl, expires, nil
+	}
+
+	now := time.Now()
+	if !expires.After(now) || (p.MaxLifetime != 0 && expires.After(now.Add(p.MaxLifetime))) {
+		return 0, time.Time{}, ErrInvalidLifetime
+	}
+
+	return ttl, expires, nil
+}
+
+// join records a client or a subscriber connecting to the clipboard, which lives on as long as one is.
+func (clip *Clipboard) join() {
+	clip.lifeMu.Lock()
+	clip.present++
+	clip.lifeMu.Unlock()
+
+	clip.poke()
+}
+
+// leave records a client or a subscriber leaving the clipboard, which starts counting down its TTL
+// once the last one has left.
+func (clip *Clipboard) leave() {
+	clip.lifeMu.Lock()
+	clip.present--
+	if clip.present == 0 {
+		clip.left = time.Now()
+	}
+	clip.lifeMu.Unlock()
+
+	clip.poke()
+}
+
+// poke wakes expire up to check the lifetime of the clipboard again.
+func (clip *Clipboard) poke() {
+	select {
+	case clip.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (clip *Clipboard) lifetime() Lifetime {
+	clip.lifeMu.Lock()
+	defer clip.lifeMu.Unlock()
+
+	var end time.Time
+	if clip.present == 0 {
+		end = clip.left.Add(clip.settings.ttl)
+	}
+
+	if expires := clip.settings.expires; !expires.IsZero() && (end.IsZero() || expires.Before(end)) {
+		end = expires
+	}
+
+	return Lifetime{TTL: clip.settings.ttl, Expires: clip.settings.expires, Present: clip.present, End: end}
+}
+
+// warning is how long before the clipboard ends its clients are warned, at most half of its TTL.
+func (clip *Clipboard) warning() time.Duration {
+	return min(ExpiryWarning, clip.settings.ttl/2)
+}
+
+func (s *ClipboardService) Lifetime(id ClipboardId) (Lifetime, error) {
+	clip := s.getClip(id)
+	if clip == nil {
+		return Lifetime{}, ErrInvalidClipId
+	}
+
+	return clip.lifetime(), nil
+}
+
+// expire ends the clipboard once nobody has been connected to it for its TTL, or once it reaches
+// its expiry. Clients which agreed on CAPABILITY_EXPIRY are warned when the end draws near, and told
+// when it no longer does.
+func (s *ClipboardService) expire(id ClipboardId) {
+	clip := s.getClip(id)
+
+	for {
+		end := clip.lifetime().End
+		now := time.Now()
+
+		clip.lifeMu.Lock()
+		warned := clip.warned
+		clip.lifeMu.Unlock()
+
+		if end.IsZero() {
+			if !warned.IsZero() {
+				s.warn(id, time.Time{})
+			}
+
+			select {
+			case <-clip.changed:
+			case <-clip.ctx.Done():
+				return
+			}
+
+			continue
+		}
+
+		if !now.Before(end) {
+			log.Errorf("[%v] clip deadline expired", id)
+			clip.cancel()
+			return
+		}
+
+		warn := end.Add(-clip.warning())
+
+		switch {
+
+		case !now.Before(warn) && !end.Equal(warned):
+			s.warn(id, end)
+
+		case now.Before(warn) && !warned.IsZero():
+			s.warn(id, time.Time{})
+
+		}
+
+		wait := end.Sub(now)
+		if now.Before(warn) {
+			wait = warn.Sub(now)
+		}
+
+		timer := time.NewTimer(wait)
+
+		select {
+
+		case <-timer.C:
+
+		case <-clip.changed:
+			timer.Stop()
+
+		case <-clip.ctx.Done():
+			timer.Stop()
+			return
+
+		}
+	}
+}
+
+// warn tells the clients which agreed on CAPABILITY_EXPIRY when the clipboard ends, a zero end
+// telling them that it no longer ends soon.
+func (s *ClipboardService) warn(id ClipboardId, end time.Time) {
+	clip := s.getClip(id)
+
+	clip.lifeMu.Lock()
+	clip.warned = end
+	clip.lifeMu.Unlock()
+
+	if end.IsZero() {
+		log.Infof("[%v] EXPIRY => * : NONE", id)
+	} else {
+		log.Infof("[%v] EXPIRY => * : %v", id, end.Format(time.RFC3339))
+	}
+
+	clip.clients.Range(func(_, value any) bool {
+		client, ok := value.(*Client)
+		if !ok {
+			panic("impossible")
+		}
+
+		if client.Capable(pb.Capability_CAPABILITY_EXPIRY) {
+			clip.router.Send(client.Cid, expiryMessage(end))
+		}
+
+		return true
+	})
+}
+
+func expiryMessage(end time.Time) net.OutMessage {
+	m := &pb.Expiry{}
+	if !end.IsZero() {
+		m.Timestamp = end.UnixMilli()
+	}
+
+	return &pb.Message{Msg: &pb.Message_Expiry{Expiry: m}}
+}
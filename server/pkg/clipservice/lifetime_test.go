@@ -0,0 +1,105 @@
+package clipservice
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyLifetime(t *testing.T) {
+	policy := Policy{
+		DefaultTTL:  time.Minute * 5,
+		MinTTL:      time.Second * 30,
+		MaxTTL:      time.Hour,
+		MaxLifetime: time.Hour * 24,
+	}
+
+	unbounded := policy
+	unbounded.MaxLifetime = 0
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		ttl     time.Duration
+		expires time.Duration // after now, 0 for never
+		want    time.Duration // TTL granted, 0 if the lifetime is refused
+	}{
+		{"default TTL", policy, 0, 0, time.Minute * 5},
+		{"chosen TTL", policy, time.Minute * 10, 0, time.Minute * 10},
+		{"shortest TTL", policy, time.Second * 30, 0, time.Second * 30},
+		{"longest TTL", policy, time.Hour, 0, time.Hour},
+		{"TTL too short", policy, time.Second * 29, 0, 0},
+		{"TTL too long", policy, time.Hour + time.Second, 0, 0},
+		{"negative TTL", policy, -time.Minute, 0, 0},
+		{"expiry", policy, 0, time.Hour, time.Minute * 5},
+		{"latest expiry", policy, 0, time.Hour*24 - time.Minute, time.Minute * 5},
+		{"expiry too late", policy, 0, time.Hour*24 + time.Minute, 0},
+		{"expiry in the past", policy, 0, -time.Minute, 0},
+		{"expiry too late without a limit", unbounded, 0, time.Hour * 24 * 365, time.Minute * 5},
+		{"expiry in the past without a limit", unbounded, 0, -time.Minute, 0},
+		{"TTL too long with an expiry", policy, time.Hour * 2, time.Hour, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expires time.Time
+			if tt.expires != 0 {
+				expires = time.Now().Add(tt.expires)
+			}
+
+			ttl, end, err := tt.policy.lifetime(tt.ttl, expires)
+
+			if tt.want == 0 {
+				if err != ErrInvalidLifetime {
+					t.Fatalf("lifetime() = %v, want %v", err, ErrInvalidLifetime)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("lifetime() = %v", err)
+			}
+
+			if ttl != tt.want {
+				t.Errorf("TTL = %v, want %v", ttl, tt.want)
+			}
+
+			if !end.Equal(expires) {
+				t.Errorf("expiry = %v, want %v", end, expires)
+			}
+		})
+	}
+}
+
+func TestClipboardLifetime(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		present int
+		ttl     time.Duration
+		expires time.Time
+		end     time.Time
+	}{
+		{"nobody connected", 0, time.Minute, time.Time{}, now.Add(time.Minute)},
+		{"somebody connected", 1, time.Minute, time.Time{}, time.Time{}},
+		{"expiry before the TTL runs out", 0, time.Hour, now.Add(time.Minute), now.Add(time.Minute)},
+		{"expiry after the TTL runs out", 0, time.Minute, now.Add(time.Hour), now.Add(time.Minute)},
+		{"expiry while somebody is connected", 2, time.Minute, now.Add(time.Hour), now.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip := &Clipboard{settings: Settings{ttl: tt.ttl, expires: tt.expires}, present: tt.present, left: now}
+
+			lifetime := clip.lifetime()
+			if !lifetime.End.Equal(tt.end) {
+				t.Errorf("end = %v, want %v", lifetime.End, tt.end)
+			}
+
+			if lifetime.Present != tt.present {
+				t.Errorf("present = %v, want %v", lifetime.Present, tt.present)
+			}
+		})
+	}
+}
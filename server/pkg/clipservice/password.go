@@ -0,0 +1,199 @@
+package clipservice
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"net/netip"
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	MaxFailedAttempts = 5 // failed attempts in a row from an address before its attempts are throttled
+	ThrottleDelay     = time.Second
+	MaxThrottleDelay  = time.Minute * 15
+	MaxThrottled      = 1024 // addresses whose failures a clipboard keeps track of
+
+	// MaxConcurrentHashes bounds the argon2 derivations running at once, each of which takes the memory
+	// of a password.
+	MaxConcurrentHashes = 4
+
+	saltSize = 16
+	keySize  = 32
+)
+
+// Password is a salted argon2id hash of the password protecting a clipboard, the parameters are kept
+// along so that changing them does not lock existing clipboards.
+type Password struct {
+	salt    []byte
+	hash    []byte
+	time    uint32
+	memory  uint32 // in KiB
+	threads uint8
+}
+
+var (
+	ErrPasswordRequired = net.NewError(pb.ErrorCode_ERROR_CODE_UNAUTHORIZED, "password required")
+	ErrWrongPassword    = net.NewError(pb.ErrorCode_ERROR_CODE_UNAUTHORIZED, "wrong password")
+	ErrRateLimited      = net.NewError(pb.ErrorCode_ERROR_CODE_RATE_LIMITED, "too many failed attempts")
+)
+
+// hashing holds a slot for each derivation running.
+var hashing = make(chan struct{}, MaxConcurrentHashes)
+
+// throttle tracks the failed attempts in a row from an address.
+type throttle struct {
+	failures int
+	retryAt  time.Time
+}
+
+func newPassword(password string) (*Password, error) {
+	salt := make([]byte, saltSize)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Password{salt: salt, time: 2, memory: 19 * 1024, threads: 1}
+	p.hash = p.derive(password)
+
+	return p, nil
+}
+
+func (p *Password) derive(password string) []byte {
+	hashing <- struct{}{}
+	defer func() { <-hashing }()
+
+	return argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, keySize)
+}
+
+func (p *Password) matches(password string) bool {
+	return subtle.ConstantTimeCompare(p.derive(password), p.hash) == 1
+}
+
+// digest is a fast hash of password, which spares deriving it again once it was found to match.
+func (p *Password) digest(password string) []byte {
+	h := sha256.New()
+	h.Write(p.salt)
+	h.Write([]byte(password))
+
+	return h.Sum(nil)
+}
+
+// Authorize checks the password and the token of a client of the clipboard connecting from addr, and
+// returns the role granted by the token.
+func (s *ClipboardService) Authorize(id ClipboardId, addr string, password string, token string) (Role, error) {
+	clip := s.getClip(id)
+	if clip == nil {
+		return RoleViewer, ErrInvalidClipId
+	}
+
+	err := clip.checkPassword(id, addr, password)
+	if err != nil {
+		return RoleViewer, err
+	}
+
+	return clip.settings.role(token)
+}
+
+// checkPassword checks password, sent from addr, against the password of the clipboard, if it has one.
+// After MaxFailedAttempts failed attempts in a row from an address, every attempt from it is refused until
+// a delay, doubling with each further failure, has passed since the last one. Once the password matched,
+// its digest is kept so that later attempts with it are not derived again.
+func (clip *Clipboard) checkPassword(id ClipboardId, addr string, password string) error {
+	p := clip.settings.password
+	if p == nil {
+		return nil
+	}
+
+	addr = throttleKey(addr)
+
+	clip.authMu.Lock()
+	defer clip.authMu.Unlock()
+
+	now := time.Now()
+
+	t := clip.throttles[addr]
+	if t != nil && now.Before(t.retryAt) {
+		return ErrRateLimited
+	}
+
+	if password == "" {
+		return ErrPasswordRequired
+	}
+
+	if clip.verified != nil && subtle.ConstantTimeCompare(p.digest(password), clip.verified) == 1 {
+		delete(clip.throttles, addr)
+		return nil
+	}
+
+	if p.matches(password) {
+		clip.verified = p.digest(password)
+		delete(clip.throttles, addr)
+		return nil
+	}
+
+	if t == nil {
+		if !clip.track() {
+			log.Errorf("[%v] wrong password, too many addresses failing", id)
+			return ErrRateLimited
+		}
+
+		t = &throttle{}
+		clip.throttles[addr] = t
+	}
+
+	t.failures++
+	if t.failures >= MaxFailedAttempts {
+		delay := ThrottleDelay << min(t.failures-MaxFailedAttempts, 10)
+		t.retryAt = now.Add(min(delay, MaxThrottleDelay))
+	}
+
+	log.Errorf("[%v] wrong password, %v failed attempts from %v", id, t.failures, addr)
+
+	return ErrWrongPassword
+}
+
+// track makes room for the failures of another address, forgetting the addresses no longer throttled if
+// MaxThrottled are tracked already. It fails if every one of them is still throttled. Must be called with
+// clip.authMu held.
+func (clip *Clipboard) track() bool {
+	if clip.throttles == nil {
+		clip.throttles = make(map[string]*throttle)
+	}
+
+	if len(clip.throttles) < MaxThrottled {
+		return true
+	}
+
+	now := time.Now()
+	for addr, t := range clip.throttles {
+		if !now.Before(t.retryAt) {
+			delete(clip.throttles, addr)
+		}
+	}
+
+	return len(clip.throttles) < MaxThrottled
+}
+
+// throttleKey returns the address attempts from addr are throttled by. IPv6 clients are throttled by
+// their /64 prefix, which they usually get whole.
+func throttleKey(addr string) string {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil || !ip.Is6() || ip.Is4In6() {
+		return addr
+	}
+
+	prefix, err := ip.Prefix(64)
+	if err != nil {
+		return addr
+	}
+
+	return prefix.String()
+}
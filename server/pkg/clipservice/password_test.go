@@ -0,0 +1,190 @@
+package clipservice
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCheckPassword(t *testing.T) {
+	password, err := newPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		failures int
+		throttle bool // whether attempts are refused when the password is checked
+		password string
+		err      error
+		after    int           // failed attempts in a row afterwards
+		delay    time.Duration // how long attempts are refused afterwards
+	}{
+		{"right password", 0, false, "secret", nil, 0, 0},
+		{"right password resets failures", 4, false, "secret", nil, 0, 0},
+		{"missing password", 2, false, "", ErrPasswordRequired, 2, 0},
+		{"first failure", 0, false, "wrong", ErrWrongPassword, 1, 0},
+		{"last failure before throttling", 3, false, "wrong", ErrWrongPassword, 4, 0},
+		{"first throttled failure", 4, false, "wrong", ErrWrongPassword, 5, ThrottleDelay},
+		{"delay doubles", 5, false, "wrong", ErrWrongPassword, 6, ThrottleDelay * 2},
+		{"delay doubles again", 8, false, "wrong", ErrWrongPassword, 9, ThrottleDelay * 16},
+		{"delay below the limit", 13, false, "wrong", ErrWrongPassword, 14, ThrottleDelay * 512},
+		{"delay capped", 14, false, "wrong", ErrWrongPassword, 15, MaxThrottleDelay},
+		{"shift capped", 100, false, "wrong", ErrWrongPassword, 101, MaxThrottleDelay},
+		{"throttled right password", 5, true, "secret", ErrRateLimited, 5, 0},
+		{"throttled wrong password", 5, true, "wrong", ErrRateLimited, 5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clip := &Clipboard{settings: Settings{password: password}}
+
+			retryAt := time.Time{}
+			if tt.throttle {
+				retryAt = time.Now().Add(time.Minute)
+			}
+
+			if tt.failures != 0 || tt.throttle {
+				clip.throttles = map[string]*throttle{"192.0.2.1": {failures: tt.failures, retryAt: retryAt}}
+			}
+
+			start := time.Now()
+			err := clip.checkPassword("test", "192.0.2.1", tt.password)
+			end := time.Now()
+
+			if err != tt.err {
+				t.Errorf("checkPassword() = %v, want %v", err, tt.err)
+			}
+
+			after := throttle{}
+			if th := clip.throttles["192.0.2.1"]; th != nil {
+				after = *th
+			}
+
+			if after.failures != tt.after {
+				t.Errorf("failures = %v, want %v", after.failures, tt.after)
+			}
+
+			switch {
+
+			case tt.throttle:
+				if !after.retryAt.Equal(retryAt) {
+					t.Errorf("retryAt moved while throttled")
+				}
+
+			case tt.delay == 0:
+				if !after.retryAt.IsZero() {
+					t.Errorf("throttled for %v, want no delay", after.retryAt.Sub(start))
+				}
+
+			case after.retryAt.Before(start.Add(tt.delay)) || after.retryAt.After(end.Add(tt.delay)):
+				t.Errorf("throttled for %v, want %v", after.retryAt.Sub(start), tt.delay)
+
+			}
+		})
+	}
+}
+
+func TestCheckPasswordAddresses(t *testing.T) {
+	password, err := newPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clip := &Clipboard{settings: Settings{password: password}}
+
+	for range MaxFailedAttempts {
+		err := clip.checkPassword("test", "2001:db8::1", "wrong")
+		if err != ErrWrongPassword {
+			t.Fatalf("checkPassword() = %v, want %v", err, ErrWrongPassword)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		addr     string
+		password string
+		err      error
+	}{
+		{"throttled address", "2001:db8::1", "secret", ErrRateLimited},
+		{"address in the same /64", "2001:db8::2", "secret", ErrRateLimited},
+		{"other address", "2001:db8:0:1::1", "secret", nil},
+		{"other address with the wrong password", "192.0.2.1", "wrong", ErrWrongPassword},
+	}
+
+	for _, tt := range tests {
+		err := clip.checkPassword("test", tt.addr, tt.password)
+		if err != tt.err {
+			t.Errorf("%v: checkPassword() = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestCheckPasswordVerified(t *testing.T) {
+	password, err := newPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clip := &Clipboard{settings: Settings{password: password}}
+
+	err = clip.checkPassword("test", "192.0.2.1", "secret")
+	if err != nil {
+		t.Fatalf("checkPassword() = %v", err)
+	}
+
+	// a password with another hash would no longer match if it were derived again
+	password.hash = nil
+
+	err = clip.checkPassword("test", "192.0.2.2", "secret")
+	if err != nil {
+		t.Errorf("checkPassword() = %v, want the verified password to match", err)
+	}
+
+	err = clip.checkPassword("test", "192.0.2.2", "wrong")
+	if err != ErrWrongPassword {
+		t.Errorf("checkPassword() = %v, want %v", err, ErrWrongPassword)
+	}
+}
+
+func TestCheckPasswordTracked(t *testing.T) {
+	password, err := newPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clip := &Clipboard{settings: Settings{password: password}, throttles: make(map[string]*throttle)}
+
+	retryAt := time.Now().Add(time.Minute)
+	for i := range MaxThrottled {
+		clip.throttles[fmt.Sprint(i)] = &throttle{failures: MaxFailedAttempts, retryAt: retryAt}
+	}
+
+	err = clip.checkPassword("test", "192.0.2.1", "wrong")
+	if err != ErrRateLimited {
+		t.Errorf("checkPassword() = %v, want %v while every address is throttled", err, ErrRateLimited)
+	}
+
+	clip.throttles["0"].retryAt = time.Now()
+
+	err = clip.checkPassword("test", "192.0.2.1", "wrong")
+	if err != ErrWrongPassword {
+		t.Errorf("checkPassword() = %v, want %v once an address is no longer throttled", err, ErrWrongPassword)
+	}
+
+	if _, ok := clip.throttles["0"]; ok {
+		t.Errorf("address no longer throttled still tracked")
+	}
+}
+
+func TestCheckPasswordUnprotected(t *testing.T) {
+	clip := &Clipboard{}
+
+	for _, password := range []string{"", "anything"} {
+		err := clip.checkPassword("test", "192.0.2.1", password)
+		if err != nil {
+			t.Errorf("checkPassword(%q) = %v, want no error", password, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package clipservice
+
+import (
+	"context"
+	"io"
+	"unicode/utf8"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	PutChunkSize = 1 << 20
+	MaxTextSize  = MaxChunkSize
+)
+
+var (
+	ErrTextTooLarge = net.NewError(pb.ErrorCode_ERROR_CODE_TOO_LARGE, "text is too large")
+	ErrInvalidText  = net.NewError(pb.ErrorCode_ERROR_CODE_UNEXPECTED_MESSAGE, "text is not valid UTF-8")
+)
+
+// Put connects a client of its own to the clipboard and talks to it the way a websocket client would,
+// so that content sent over plain HTTP goes through processText and processFile and is synced
+// to the other clients.
+type Put struct {
+	s      *ClipboardService
+	id     ClipboardId
+	client *Client
+}
+
+func (s *ClipboardService) Put(id ClipboardId, ctx context.Context, role Role) (*Put, error) {
+	client, err := s.connect(id, ctx, role, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Put{s, id, client}, nil
+}
+
+func (p *Put) Close() {
+	p.client.Cancel()
+}
+
+func (p *Put) send(m *pb.Message) {
+	p.client.In <- net.InMessage{Cid: p.client.Cid, Message: m}
+}
+
+// recv returns the next message sent to the client, turning errors into a net.Error.
+func (p *Put) recv() (*pb.Message, error) {
+	m, ok := <-p.client.Out
+	if !ok {
+		return nil, ErrClientDisconnected
+	}
+
+	if e := m.GetErr(); e != nil {
+		return nil, net.NewError(e.GetCode(), e.GetDesc())
+	}
+
+	return m, nil
+}
+
+// Text replaces the items of the clipboard with data, or pushes it on top of them if add is set.
+func (p *Put) Text(data string, add bool) error {
+	if len(data) > MaxTextSize {
+		return ErrTextTooLarge
+	}
+
+	if !utf8.ValidString(data) {
+		return ErrInvalidText
+	}
+
+	text := &pb.Text{Data: data}
+	if add {
+		p.send(&pb.Message{Msg: &pb.Message_AddItem{AddItem: &pb.AddItem{Content: &pb.AddItem_Text{Text: text}}}})
+	} else {
+		p.send(&pb.Message{Msg: &pb.Message_Text{Text: text}})
+	}
+
+	for {
+		m, err := p.recv()
+		if err != nil {
+			return err
+		}
+
+		if m.GetAck() != nil {
+			log.Infof("[%v] PUT %v : TXT", p.id, p.client.Cid)
+			return nil
+		}
+	}
+}
+
+// File uploads size bytes read from r as a file, replacing the items of the clipboard unless add is set.
+func (p *Put) File(filename string, contentType string, size int64, r io.Reader, add bool) error {
+	numChunks := int((size + PutChunkSize - 1) / PutChunkSize)
+
+	hdr := &pb.FileHeader{
+		Filename:    filename,
+		ContentType: contentType,
+		NumChunks:   int32(numChunks),
+		Window:      MaxWindow,
+	}
+	if add {
+		p.send(&pb.Message{Msg: &pb.Message_AddItem{AddItem: &pb.AddItem{Content: &pb.AddItem_Hdr{Hdr: hdr}}}})
+	} else {
+		p.send(&pb.Message{Msg: &pb.Message_Hdr{Hdr: hdr}})
+	}
+
+	credits, idx := 0, 0
+	for {
+		m, err := p.recv()
+		if err != nil {
+			return err
+		}
+
+		if m.GetAck() != nil {
+			log.Infof("[%v] PUT %v : FILE %v/%v", p.id, p.client.Cid, filename, numChunks)
+			return nil
+		}
+
+		next := m.GetNextChunk()
+		if next == nil {
+			continue
+		}
+
+		credits += int(next.GetCredits())
+
+		for ; credits > 0 && idx < numChunks; credits-- {
+			data := make([]byte, min(PutChunkSize, size-int64(idx)*PutChunkSize))
+
+			_, err := io.ReadFull(r, data)
+			if err != nil {
+				p.send(&pb.Message{Msg: &pb.Message_Cancel{Cancel: &pb.Cancel{}}})
+				return err
+			}
+
+			p.send(&pb.Message{Msg: &pb.Message_Chunk{Chunk: &pb.Chunk{Index: int32(idx), Data: data}}})
+			idx++
+		}
+	}
+}
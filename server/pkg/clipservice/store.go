@@ -0,0 +1,90 @@
+package clipservice
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// ContentStore keeps the settings and the history of every clipboard, the last version being its current content.
+type ContentStore interface {
+	Load(id ClipboardId) (Settings, []Version, error)
+	Save(id ClipboardId, settings Settings, history []Version) error
+	Delete(id ClipboardId) error
+	List() ([]ClipboardId, error)
+
+	// BlobDir is the directory holding the spooled chunks of files stored in a clipboard.
+	BlobDir(id ClipboardId) string
+}
+
+type MemoryStore struct {
+	clips sync.Map
+	dir   string
+}
+
+type memoryClip struct {
+	settings Settings
+	history  []Version
+}
+
+var ErrNotStored = errors.New("clipboard is not stored")
+
+// NewMemoryStore removes the blobs spooled by a previous run, since the clipboards holding them are gone.
+func NewMemoryStore() *MemoryStore {
+	dir := filepath.Join(os.TempDir(), "mutclip")
+
+	err := os.RemoveAll(dir)
+	if err != nil {
+		log.Error(err)
+	}
+
+	return &MemoryStore{dir: dir}
+}
+
+func (s *MemoryStore) Load(id ClipboardId) (Settings, []Version, error) {
+	a, ok := s.clips.Load(id)
+	if !ok {
+		return Settings{}, nil, ErrNotStored
+	}
+
+	clip, ok := a.(memoryClip)
+	if !ok {
+		panic("impossible")
+	}
+
+	return clip.settings, clip.history, nil
+}
+
+func (s *MemoryStore) Save(id ClipboardId, settings Settings, history []Version) error {
+	s.clips.Store(id, memoryClip{settings, slices.Clone(history)})
+	return nil
+}
+
+func (s *MemoryStore) Delete(id ClipboardId) error {
+	s.clips.Delete(id)
+	return os.RemoveAll(filepath.Join(s.dir, id))
+}
+
+func (s *MemoryStore) List() ([]ClipboardId, error) {
+	var ids []ClipboardId
+	s.clips.Range(func(key, _ any) bool {
+		id, ok := key.(ClipboardId)
+		if !ok {
+			panic("impossible")
+		}
+
+		ids = append(ids, id)
+
+		return true
+	})
+
+	return ids, nil
+}
+
+func (s *MemoryStore) BlobDir(id ClipboardId) string {
+	return filepath.Join(s.dir, id)
+}
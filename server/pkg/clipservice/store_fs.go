@@ -0,0 +1,372 @@
+package clipservice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+)
+
+type FileStore struct {
+	dir string
+}
+
+type storedContent struct {
+	Kind        string          `json:"kind"`
+	Text        string          `json:"text,omitempty"`
+	Ciphertext  []byte          `json:"ciphertext,omitempty"`
+	Filename    string          `json:"filename,omitempty"`
+	ContentType string          `json:"contentType,omitempty"`
+	NumChunks   int             `json:"numChunks,omitempty"`
+	Session     string          `json:"session,omitempty"`
+	Blob        string          `json:"blob,omitempty"`
+	Offsets     []int64         `json:"offsets,omitempty"`
+	Digest      []byte          `json:"digest,omitempty"`
+	Entries     []storedEntry   `json:"entries,omitempty"`
+	Envelope    *storedEnvelope `json:"envelope,omitempty"`
+}
+
+type storedEnvelope struct {
+	Cipher     int32  `json:"cipher"`
+	Nonce      []byte `json:"nonce"`
+	Kdf        int32  `json:"kdf"`
+	Salt       []byte `json:"salt,omitempty"`
+	Iterations uint32 `json:"iterations,omitempty"`
+	Metadata   []byte `json:"metadata,omitempty"`
+}
+
+type storedEntry struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Mode uint32 `json:"mode"`
+	Dir  bool   `json:"dir,omitempty"`
+}
+
+type storedItem struct {
+	Id       string        `json:"id"`
+	Author   net.CID       `json:"author"`
+	Uploader []byte        `json:"uploader,omitempty"`
+	Created  time.Time     `json:"created"`
+	Content  storedContent `json:"content"`
+}
+
+type storedVersion struct {
+	Id      int            `json:"id"`
+	Author  net.CID        `json:"author"`
+	Created time.Time      `json:"created"`
+	Content *storedContent `json:"content,omitempty"` // versions stored before clipboards held several items
+	Items   []storedItem   `json:"items,omitempty"`
+}
+
+type storedPassword struct {
+	Salt    []byte `json:"salt"`
+	Hash    []byte `json:"hash"`
+	Time    uint32 `json:"time"`
+	Memory  uint32 `json:"memory"`
+	Threads uint8  `json:"threads"`
+}
+
+type storedClip struct {
+	storedContent // clipboards stored before history was kept
+
+	Password    *storedPassword `json:"password,omitempty"`
+	Encrypted   bool            `json:"encrypted,omitempty"`
+	Burn        bool            `json:"burn,omitempty"`
+	TTL         time.Duration   `json:"ttl,omitempty"`
+	Expires     *time.Time      `json:"expires,omitempty"`
+	EditorToken []byte          `json:"editorToken,omitempty"`
+	ViewerToken []byte          `json:"viewerToken,omitempty"`
+	Versions    []storedVersion `json:"versions,omitempty"`
+}
+
+const (
+	kindText = "text"
+	kindFile = "file"
+
+	contentFilename = "content.json"
+	blobsDirname    = "blobs"
+	tmpSuffix       = ".tmp"
+)
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.New("store directory is not set")
+	}
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileStore{dir}, nil
+}
+
+func (s *FileStore) path(id ClipboardId) string {
+	return filepath.Join(s.dir, id)
+}
+
+func (s *FileStore) Load(id ClipboardId) (Settings, []Version, error) {
+	buf, err := os.ReadFile(filepath.Join(s.path(id), contentFilename))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Settings{}, nil, ErrNotStored
+	}
+	if err != nil {
+		return Settings{}, nil, err
+	}
+
+	var stored storedClip
+	err = json.Unmarshal(buf, &stored)
+	if err != nil {
+		return Settings{}, nil, err
+	}
+
+	settings := Settings{
+		encrypted:   stored.Encrypted,
+		burn:        stored.Burn,
+		ttl:         stored.TTL,
+		editorToken: stored.EditorToken,
+		viewerToken: stored.ViewerToken,
+	}
+	if stored.Expires != nil {
+		settings.expires = *stored.Expires
+	}
+	if p := stored.Password; p != nil {
+		settings.password = &Password{salt: p.Salt, hash: p.Hash, time: p.Time, memory: p.Memory, threads: p.Threads}
+	}
+
+	if len(stored.Versions) == 0 {
+		stored.Versions = []storedVersion{{Id: 1, Created: time.Now(), Content: &stored.storedContent}}
+	}
+
+	var history []Version
+	for _, v := range stored.Versions {
+		if v.Content != nil && len(v.Items) == 0 && (v.Content.Kind != kindText || v.Content.Text != "") {
+			v.Items = []storedItem{{Id: newItemId(), Author: v.Author, Created: v.Created, Content: *v.Content}}
+		}
+
+		var items []Item
+		for _, item := range v.Items {
+			content, err := s.decode(id, item.Content)
+			if err != nil {
+				return Settings{}, nil, err
+			}
+
+			items = append(items, Item{id: item.Id, content: content, author: item.Author, uploader: item.Uploader, created: item.Created})
+		}
+
+		history = append(history, Version{
+			id:      v.Id,
+			items:   items,
+			author:  v.Author,
+			created: v.Created,
+		})
+	}
+
+	return settings, history, nil
+}
+
+func (s *FileStore) decode(id ClipboardId, stored storedContent) (Content, error) {
+	switch stored.Kind {
+
+	case kindText:
+		if stored.Envelope != nil {
+			return ContentText{data: string(stored.Ciphertext), envelope: decodeEnvelope(stored.Envelope)}, nil
+		}
+
+		return ContentText{data: stored.Text}, nil
+
+	case kindFile:
+		if len(stored.Offsets) != stored.NumChunks+1 {
+			return nil, fmt.Errorf("file %v has %v offsets for %v chunks", stored.Filename, len(stored.Offsets), stored.NumChunks)
+		}
+
+		_, err := os.Stat(filepath.Join(s.BlobDir(id), stored.Blob))
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []Entry
+		for _, entry := range stored.Entries {
+			entries = append(entries, Entry{path: entry.Path, size: entry.Size, mode: entry.Mode, dir: entry.Dir})
+		}
+
+		entries, err = layout(entries)
+		if err != nil {
+			return nil, fmt.Errorf("file %v has an invalid manifest", stored.Filename)
+		}
+
+		return ContentFile{
+			ready:          true,
+			session:        stored.Session,
+			blob:           stored.Blob,
+			offsets:        stored.Offsets,
+			digest:         stored.Digest,
+			nextChunkIndex: stored.NumChunks,
+			numChunks:      stored.NumChunks,
+			contentType:    stored.ContentType,
+			filename:       stored.Filename,
+			entries:        entries,
+			envelope:       decodeEnvelope(stored.Envelope),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown kind of stored content: %v", stored.Kind)
+
+	}
+}
+
+func encode(content Content) (storedContent, error) {
+	switch content := content.(type) {
+
+	case ContentText:
+		if content.envelope != nil {
+			return storedContent{Kind: kindText, Ciphertext: []byte(content.data), Envelope: encodeEnvelope(content.envelope)}, nil
+		}
+
+		return storedContent{Kind: kindText, Text: content.data}, nil
+
+	case ContentFile:
+		if !content.ready {
+			return storedContent{}, fmt.Errorf("file %v is not ready", content.filename)
+		}
+
+		var entries []storedEntry
+		for _, entry := range content.entries {
+			entries = append(entries, storedEntry{Path: entry.path, Size: entry.size, Mode: entry.mode, Dir: entry.dir})
+		}
+
+		return storedContent{
+			Kind:        kindFile,
+			Filename:    content.filename,
+			ContentType: content.contentType,
+			NumChunks:   content.numChunks,
+			Session:     content.session,
+			Blob:        content.blob,
+			Offsets:     content.offsets,
+			Digest:      content.digest,
+			Entries:     entries,
+			Envelope:    encodeEnvelope(content.envelope),
+		}, nil
+
+	default:
+		panic("impossible")
+
+	}
+}
+
+func decodeEnvelope(stored *storedEnvelope) *Envelope {
+	if stored == nil {
+		return nil
+	}
+
+	return &Envelope{
+		cipher:     pb.Cipher(stored.Cipher),
+		nonce:      stored.Nonce,
+		kdf:        pb.KdfAlgorithm(stored.Kdf),
+		salt:       stored.Salt,
+		iterations: stored.Iterations,
+		metadata:   stored.Metadata,
+	}
+}
+
+func encodeEnvelope(envelope *Envelope) *storedEnvelope {
+	if envelope == nil {
+		return nil
+	}
+
+	return &storedEnvelope{
+		Cipher:     int32(envelope.cipher),
+		Nonce:      envelope.nonce,
+		Kdf:        int32(envelope.kdf),
+		Salt:       envelope.salt,
+		Iterations: envelope.iterations,
+		Metadata:   envelope.metadata,
+	}
+}
+
+func (s *FileStore) Save(id ClipboardId, settings Settings, history []Version) error {
+	stored := storedClip{
+		Encrypted:   settings.encrypted,
+		Burn:        settings.burn,
+		TTL:         settings.ttl,
+		EditorToken: settings.editorToken,
+		ViewerToken: settings.viewerToken,
+	}
+	if !settings.expires.IsZero() {
+		stored.Expires = &settings.expires
+	}
+
+	if p := settings.password; p != nil {
+		stored.Password = &storedPassword{Salt: p.salt, Hash: p.hash, Time: p.time, Memory: p.memory, Threads: p.threads}
+	}
+
+	for _, v := range history {
+		var items []storedItem
+		for _, item := range v.items {
+			content, err := encode(item.content)
+			if err != nil {
+				return err
+			}
+
+			items = append(items, storedItem{Id: item.id, Author: item.author, Uploader: item.uploader, Created: item.created, Content: content})
+		}
+
+		stored.Versions = append(stored.Versions, storedVersion{
+			Id:      v.id,
+			Author:  v.author,
+			Created: v.created,
+			Items:   items,
+		})
+	}
+
+	buf, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(s.path(id), 0o755)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.path(id), contentFilename)
+
+	err = os.WriteFile(path+tmpSuffix, buf, 0o644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(path+tmpSuffix, path)
+}
+
+func (s *FileStore) Delete(id ClipboardId) error {
+	return os.RemoveAll(s.path(id))
+}
+
+func (s *FileStore) List() ([]ClipboardId, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []ClipboardId
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		ids = append(ids, entry.Name())
+	}
+
+	return ids, nil
+}
+
+func (s *FileStore) BlobDir(id ClipboardId) string {
+	return filepath.Join(s.path(id), blobsDirname)
+}
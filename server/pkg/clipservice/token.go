@@ -0,0 +1,98 @@
+package clipservice
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+)
+
+const tokenSize = 16
+
+// Role is what a client may do with a clipboard, as granted by the token it connected with.
+type Role int
+
+const (
+	RoleViewer Role = iota
+	RoleEditor
+)
+
+// Tokens are the capability tokens minted along with a clipboard, only their hashes are kept.
+type Tokens struct {
+	Editor string
+	Viewer string
+}
+
+var (
+	ErrInvalidToken = net.NewError(pb.ErrorCode_ERROR_CODE_UNAUTHORIZED, "invalid token")
+	ErrReadOnly     = net.NewError(pb.ErrorCode_ERROR_CODE_UNAUTHORIZED, "viewers cannot change the clipboard")
+)
+
+func (r Role) String() string {
+	switch r {
+
+	case RoleEditor:
+		return "editor"
+
+	default:
+		return "viewer"
+
+	}
+}
+
+func newToken() (string, []byte, error) {
+	buf := make([]byte, tokenSize)
+	_, err := rand.Read(buf)
+	if err != nil {
+		return "", nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// role returns the role granted by token. Clipboards generated before tokens existed have none,
+// and let everyone edit them.
+func (settings Settings) role(token string) (Role, error) {
+	if settings.editorToken == nil {
+		return RoleEditor, nil
+	}
+
+	hash := hashToken(token)
+
+	switch {
+
+	case subtle.ConstantTimeCompare(hash, settings.editorToken) == 1:
+		return RoleEditor, nil
+
+	case subtle.ConstantTimeCompare(hash, settings.viewerToken) == 1:
+		return RoleViewer, nil
+
+	default:
+		return RoleViewer, ErrInvalidToken
+
+	}
+}
+
+// changes reports whether m changes the clipboard, which viewers may not do.
+func changes(m *pb.Message) bool {
+	switch m.Msg.(type) {
+
+	case *pb.Message_Text, *pb.Message_Hdr, *pb.Message_AddItem, *pb.Message_RemoveItem,
+		*pb.Message_MoveItem, *pb.Message_RestoreVersion, *pb.Message_Cancel:
+		return true
+
+	default:
+		return false
+
+	}
+}
@@ -0,0 +1,148 @@
+package clipservice
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+)
+
+// Entry is a file or a directory of a tree uploaded in a single transfer. The files are stored one
+// after another in the blob, in the order of the manifest, and no chunk spans two of them.
+type Entry struct {
+	path   string
+	size   int64
+	mode   uint32
+	dir    bool
+	offset int64 // where the file starts in the blob
+}
+
+var (
+	ErrInvalidManifest = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_MANIFEST, "invalid manifest")
+	ErrInvalidPath     = net.NewError(pb.ErrorCode_ERROR_CODE_INVALID_PATH, "invalid path")
+)
+
+func newEntries(m *pb.Manifest) ([]Entry, error) {
+	var entries []Entry
+	for _, entry := range m.GetEntries() {
+		entries = append(entries, Entry{
+			path: entry.GetPath(),
+			size: entry.GetSize(),
+			mode: entry.GetMode(),
+			dir:  entry.GetDir(),
+		})
+	}
+
+	return layout(entries)
+}
+
+// layout checks the entries of a manifest and places their files in the blob. Paths must be unique,
+// and no entry may lie under a file.
+func layout(entries []Entry) ([]Entry, error) {
+	dirs := make(map[string]bool)
+
+	offset := int64(0)
+	for i, entry := range entries {
+		if !validPath(entry.path) || entry.size < 0 || entry.mode&^0o777 != 0 || entry.dir && entry.size != 0 {
+			return nil, ErrInvalidManifest
+		}
+
+		if _, ok := dirs[entry.path]; ok {
+			return nil, ErrInvalidManifest
+		}
+		dirs[entry.path] = entry.dir
+
+		entries[i].offset = offset
+		offset += entry.size
+	}
+
+	for _, entry := range entries {
+		for p := path.Dir(entry.path); p != "."; p = path.Dir(p) {
+			if dir, ok := dirs[p]; ok && !dir {
+				return nil, ErrInvalidManifest
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func validPath(p string) bool {
+	return p != "" && p != "." && p != ".." &&
+		path.Clean(p) == p && !path.IsAbs(p) &&
+		!strings.HasPrefix(p, "../") && !strings.Contains(p, "\\")
+}
+
+// validFilename tells whether name names a single file, which clients may create where they are
+// without leaving it.
+func validFilename(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, "/\\\x00")
+}
+
+// size returns the number of bytes taken by the files of the tree.
+func (f ContentFile) size() int64 {
+	if len(f.entries) == 0 {
+		return 0
+	}
+
+	last := f.entries[len(f.entries)-1]
+	return last.offset + last.size
+}
+
+// boundary returns where the file holding the byte at offset ends in the blob. It fails if the offset
+// is past the files of the manifest.
+func (f ContentFile) boundary(offset int64) (int64, bool) {
+	for _, entry := range f.entries {
+		if end := entry.offset + entry.size; offset < end {
+			return end, true
+		}
+	}
+
+	return 0, false
+}
+
+func (f ContentFile) findEntry(p string) (Entry, bool) {
+	for _, entry := range f.entries {
+		if entry.path == p {
+			return entry, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// entryChunks returns the chunks holding a file of the tree. Must only be called on a ready file.
+func (f ContentFile) entryChunks(entry Entry) (int, int) {
+	find := func(offset int64) int {
+		return sort.Search(f.numChunks, func(i int) bool { return f.offsets[i] >= offset })
+	}
+
+	return find(entry.offset), find(entry.offset + entry.size)
+}
+
+func (f ContentFile) manifest() *pb.Manifest {
+	if len(f.entries) == 0 {
+		return nil
+	}
+
+	m := &pb.Manifest{}
+	for _, entry := range f.entries {
+		e := &pb.Entry{
+			Path: entry.path,
+			Size: entry.size,
+			Mode: entry.mode,
+			Dir:  entry.dir,
+		}
+
+		if f.ready && !entry.dir {
+			start, end := f.entryChunks(entry)
+			e.Start, e.End = int32(start), int32(end)
+		}
+
+		m.Entries = append(m.Entries, e)
+	}
+
+	return m
+}
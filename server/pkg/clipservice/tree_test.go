@@ -0,0 +1,179 @@
+package clipservice
+
+import "testing"
+
+func TestValidPath(t *testing.T) {
+	tests := []struct {
+		path  string
+		valid bool
+	}{
+		{"a", true},
+		{"a/b.txt", true},
+		{"a/..b", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"../a", false},
+		{"a/..", false},
+		{"a/../b", false},
+		{"a/../../b", false},
+		{"./a", false},
+		{"a/", false},
+		{"a//b", false},
+		{"/a", false},
+		{"a\\b", false},
+		{"..\\a", false},
+	}
+
+	for _, tt := range tests {
+		if valid := validPath(tt.path); valid != tt.valid {
+			t.Errorf("validPath(%q) = %v, want %v", tt.path, valid, tt.valid)
+		}
+	}
+}
+
+func TestValidFilename(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"a.txt", true},
+		{"..a", true},
+		{".a", true},
+		{"", false},
+		{".", false},
+		{"..", false},
+		{"a/b", false},
+		{"/a", false},
+		{"../a", false},
+		{"a\\b", false},
+		{"..\\a", false},
+		{"a\x00", false},
+	}
+
+	for _, tt := range tests {
+		if valid := validFilename(tt.name); valid != tt.valid {
+			t.Errorf("validFilename(%q) = %v, want %v", tt.name, valid, tt.valid)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+		offsets []int64
+	}{
+		{
+			name: "files after one another",
+			entries: []Entry{
+				{path: "a", dir: true},
+				{path: "a/b", size: 3},
+				{path: "a/c", size: 5},
+				{path: "d", size: 1},
+			},
+			offsets: []int64{0, 0, 3, 8},
+		},
+		{
+			name:    "empty tree",
+			entries: nil,
+			offsets: nil,
+		},
+		{
+			name: "parent listed after its children",
+			entries: []Entry{
+				{path: "a/b", size: 2},
+				{path: "a", dir: true},
+			},
+			offsets: []int64{0, 2},
+		},
+		{
+			name:    "parent not listed",
+			entries: []Entry{{path: "a/b/c", size: 2}},
+			offsets: []int64{0},
+		},
+		{
+			name:    "dot dot path",
+			entries: []Entry{{path: "../a", size: 1}},
+		},
+		{
+			name:    "dot dot inside path",
+			entries: []Entry{{path: "a/../../b", size: 1}},
+		},
+		{
+			name:    "absolute path",
+			entries: []Entry{{path: "/etc/passwd", size: 1}},
+		},
+		{
+			name: "duplicate files",
+			entries: []Entry{
+				{path: "a", size: 1},
+				{path: "a", size: 2},
+			},
+		},
+		{
+			name: "duplicate directories",
+			entries: []Entry{
+				{path: "a", dir: true},
+				{path: "a", dir: true},
+			},
+		},
+		{
+			name: "file and directory with the same path",
+			entries: []Entry{
+				{path: "a", dir: true},
+				{path: "a", size: 1},
+			},
+		},
+		{
+			name: "entry under a file",
+			entries: []Entry{
+				{path: "a", size: 1},
+				{path: "a/b", size: 1},
+			},
+		},
+		{
+			name: "entry under a file listed after it",
+			entries: []Entry{
+				{path: "a/b/c", dir: true},
+				{path: "a/b", size: 1},
+			},
+		},
+		{
+			name:    "negative size",
+			entries: []Entry{{path: "a", size: -1}},
+		},
+		{
+			name:    "directory with a size",
+			entries: []Entry{{path: "a", size: 1, dir: true}},
+		},
+		{
+			name:    "mode beyond permissions",
+			entries: []Entry{{path: "a", size: 1, mode: 0o4755}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := layout(tt.entries)
+
+			if tt.offsets == nil && len(tt.entries) != 0 {
+				if err != ErrInvalidManifest {
+					t.Fatalf("layout() = %v, want %v", err, ErrInvalidManifest)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("layout() = %v", err)
+			}
+
+			for i, entry := range entries {
+				if entry.offset != tt.offsets[i] {
+					t.Errorf("offset of %v = %v, want %v", entry.path, entry.offset, tt.offsets[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package clipservice
+
+import (
+	"hash"
+	"os"
+	"sync"
+	"time"
+
+	"mutclip/pkg/net"
+	pb "mutclip/pkg/pb/clip"
+
+	"github.com/charmbracelet/log"
+)
+
+const ResumeDeadline = time.Minute
+
+// upload is a file being received into a clipboard. It outlives the connection of the uploader,
+// so that a client which reconnects with the same session can continue where it stopped.
+type upload struct {
+	sync.Mutex // held by the goroutine receiving chunks
+
+	session  string
+	cid      net.CID
+	uploader []byte // identity of the client which started the upload, see Client.uploader
+	tun      *net.Tunnel
+	blob     *os.File
+	blobName string
+	hash     hash.Hash // running SHA-256 of the received chunks
+	digest   []byte    // SHA-256 announced by the uploader
+	file     ContentFile
+	add      bool // whether the file is pushed as a new item instead of replacing all of them
+	expiry   *time.Timer
+}
+
+// resume hands the upload over to cid and returns the tunnel of the previous uploader, if it is still open.
+// Must be called with clip.mu held.
+func (up *upload) resume(cid net.CID) *net.Tunnel {
+	if up.expiry != nil {
+		up.expiry.Stop()
+		up.expiry = nil
+	}
+
+	tun := up.tun
+
+	up.cid = cid
+	up.tun = nil
+
+	return tun
+}
+
+// busy reports the upload in progress to a client whose content was rejected. Must be called with clip.mu held.
+func (clip *Clipboard) busy() net.OutMessage {
+	file := clip.upload.file
+
+	return net.Busy(ErrBusy, &pb.Busy{
+		Uploader:  clip.upload.cid.String(),
+		Filename:  file.filename,
+		Received:  int32(file.nextChunkIndex),
+		NumChunks: int32(file.numChunks),
+	})
+}
+
+// abortUpload drops the upload and the chunks received so far. Must be called with clip.mu held.
+func (s *ClipboardService) abortUpload(id ClipboardId, up *upload) {
+	clip := s.getClip(id)
+	if clip.upload != up {
+		return
+	}
+
+	if up.expiry != nil {
+		up.expiry.Stop()
+	}
+
+	clip.upload = nil
+
+	err := up.blob.Close()
+	if err != nil {
+		log.Error(err)
+	}
+
+	err = s.removeBlob(id, up.blobName)
+	if err != nil {
+		log.Error(err)
+	}
+
+	log.Infof("[%v] $ <= %v : ABORT", id, up.cid)
+}
+
+// detachUpload is called when the uploader disconnects. Uploads without a session cannot be resumed
+// and are aborted immediately, others are kept for ResumeDeadline.
+func (s *ClipboardService) detachUpload(id ClipboardId, cid net.CID, up *upload) {
+	clip := s.getClip(id)
+
+	clip.mu.Lock()
+	defer clip.mu.Unlock()
+
+	if clip.upload != up || up.cid != cid {
+		return
+	}
+
+	if up.session == "" {
+		s.abortUpload(id, up)
+		return
+	}
+
+	log.Infof("[%v] $ <= %v : DETACH %v", id, cid, up.session)
+
+	up.tun = nil
+
+	var expiry *time.Timer
+	expiry = time.AfterFunc(ResumeDeadline, func() {
+		clip.mu.Lock()
+		defer clip.mu.Unlock()
+
+		// resume stops the timer, but it may already be waiting for the lock, and the upload may
+		// have been resumed by the same client
+		if up.expiry != expiry {
+			return
+		}
+
+		log.Errorf("upload session %v expired", up.session)
+		s.abortUpload(id, up)
+	})
+	up.expiry = expiry
+}
+
+// cancelUpload aborts the upload on request of cid and tells the other clients about it.
+func (s *ClipboardService) cancelUpload(id ClipboardId, cid net.CID, up *upload) {
+	clip := s.getClip(id)
+	r := clip.router
+
+	clip.mu.Lock()
+
+	if clip.upload != up {
+		clip.mu.Unlock()
+
+		r.Send(cid, net.Err(net.ErrUnexpected))
+		return
+	}
+
+	session := up.file.session
+	tun := up.tun
+
+	s.abortUpload(id, up)
+
+	clip.mu.Unlock()
+
+	if tun != nil {
+		tun.Cancel()
+	}
+
+	log.Infof("[%v] CANCEL -> @ : %v", id, session)
+
+	r.Broadcast(
+		&pb.Message{Msg: &pb.Message_Cancel{Cancel: &pb.Cancel{Session: session}}},
+		map[net.CID]struct{}{cid: {}},
+	)
+
+	err := r.Send(cid, &pb.Message{Msg: &pb.Message_Ack{Ack: &pb.Ack{}}})
+	if err != nil {
+		log.Error(err)
+	}
+
+	log.Infof("[%v] ACK => %v", id, cid)
+}
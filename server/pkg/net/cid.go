@@ -12,3 +12,11 @@ func (cid CID) String() string {
 	str := uuid.UUID(cid).String()
 	return str[:8]
 }
+
+func (cid CID) MarshalText() ([]byte, error) {
+	return uuid.UUID(cid).MarshalText()
+}
+
+func (cid *CID) UnmarshalText(b []byte) error {
+	return (*uuid.UUID)(cid).UnmarshalText(b)
+}
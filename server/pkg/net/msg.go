@@ -1,6 +1,8 @@
 package net
 
 import (
+	"errors"
+
 	pb "mutclip/pkg/pb/clip"
 
 	"google.golang.org/protobuf/proto"
@@ -13,6 +15,36 @@ type InMessage struct {
 
 type OutMessage = *pb.Message
 
+// Error is an error reported to clients together with a code they can branch on.
+type Error struct {
+	Code pb.ErrorCode
+	Desc string
+}
+
+var (
+	ErrUnexpected = NewError(pb.ErrorCode_ERROR_CODE_UNEXPECTED_MESSAGE, "unexpected message")
+	ErrInternal   = NewError(pb.ErrorCode_ERROR_CODE_INTERNAL, "internal server error")
+	ErrDisordered = NewError(pb.ErrorCode_ERROR_CODE_DISORDERED, "transmission disordered")
+)
+
+func NewError(code pb.ErrorCode, desc string) error {
+	return &Error{Code: code, Desc: desc}
+}
+
+func (e *Error) Error() string {
+	return e.Desc
+}
+
+// Code returns the code of the first Error in the chain of err.
+func Code(err error) pb.ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+
+	return pb.ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
 func In(cid CID, b []byte) (*InMessage, error) {
 	m := &pb.Message{}
 
@@ -34,9 +66,13 @@ func Out(m OutMessage) []byte {
 }
 
 func Err(err error) OutMessage {
-	return &pb.Message{Msg: &pb.Message_Err{Err: &pb.Error{Desc: err.Error(), Fatal: false}}}
+	return &pb.Message{Msg: &pb.Message_Err{Err: &pb.Error{Desc: err.Error(), Code: Code(err), Fatal: false}}}
 }
 
 func Fatal(err error) OutMessage {
-	return &pb.Message{Msg: &pb.Message_Err{Err: &pb.Error{Desc: err.Error(), Fatal: true}}}
+	return &pb.Message{Msg: &pb.Message_Err{Err: &pb.Error{Desc: err.Error(), Code: Code(err), Fatal: true}}}
+}
+
+func Busy(err error, busy *pb.Busy) OutMessage {
+	return &pb.Message{Msg: &pb.Message_Err{Err: &pb.Error{Desc: err.Error(), Code: Code(err), Fatal: false, Kind: &pb.Error_Busy{Busy: busy}}}}
 }
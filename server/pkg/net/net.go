@@ -131,11 +131,16 @@ func (r *Router) Tunnel(cid CID) (*Tunnel, error) {
 
 	tun := &Tunnel{
 		Context: ctx,
-		Cancel:  cancel,
 		In:      in,
 		Out:     out,
 	}
 
+	// the tunnel is removed as soon as it is cancelled, so that its owner can open another one right away
+	tun.Cancel = func() {
+		r.tunnels.CompareAndDelete(cid, tun)
+		cancel()
+	}
+
 	r.tunnels.Store(cid, tun)
 
 	go func() {
@@ -147,19 +152,37 @@ func (r *Router) Tunnel(cid CID) (*Tunnel, error) {
 		cancel()
 	}()
 
+	// out is never closed, the tunnel may be cancelled by another goroutine while its owner is still sending
 	go func() {
-		for m := range out {
-			conn.out <- m
+		for {
+			select {
+
+			case m := <-out:
+				conn.out <- m
+
+			case <-ctx.Done():
+				for {
+					select {
+
+					case m := <-out:
+						conn.out <- m
+
+					default:
+						return
+
+					}
+				}
+
+			}
 		}
 	}()
 
 	go func() {
 		<-ctx.Done()
 
-		close(in)
-		close(out)
+		r.tunnels.CompareAndDelete(cid, tun)
 
-		r.tunnels.Delete(cid)
+		close(in)
 	}()
 
 	return tun, nil
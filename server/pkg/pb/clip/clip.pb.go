@@ -21,6 +21,277 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Cipher int32
+
+const (
+	Cipher_CIPHER_UNSPECIFIED        Cipher = 0
+	Cipher_CIPHER_AES_256_GCM        Cipher = 1
+	Cipher_CIPHER_XCHACHA20_POLY1305 Cipher = 2
+)
+
+// Enum value maps for Cipher.
+var (
+	Cipher_name = map[int32]string{
+		0: "CIPHER_UNSPECIFIED",
+		1: "CIPHER_AES_256_GCM",
+		2: "CIPHER_XCHACHA20_POLY1305",
+	}
+	Cipher_value = map[string]int32{
+		"CIPHER_UNSPECIFIED":        0,
+		"CIPHER_AES_256_GCM":        1,
+		"CIPHER_XCHACHA20_POLY1305": 2,
+	}
+)
+
+func (x Cipher) Enum() *Cipher {
+	p := new(Cipher)
+	*p = x
+	return p
+}
+
+func (x Cipher) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Cipher) Descriptor() protoreflect.EnumDescriptor {
+	return file_clip_proto_enumTypes[0].Descriptor()
+}
+
+func (Cipher) Type() protoreflect.EnumType {
+	return &file_clip_proto_enumTypes[0]
+}
+
+func (x Cipher) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Cipher.Descriptor instead.
+func (Cipher) EnumDescriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{0}
+}
+
+type KdfAlgorithm int32
+
+const (
+	KdfAlgorithm_KDF_ALGORITHM_NONE          KdfAlgorithm = 0
+	KdfAlgorithm_KDF_ALGORITHM_HKDF_SHA256   KdfAlgorithm = 1
+	KdfAlgorithm_KDF_ALGORITHM_PBKDF2_SHA256 KdfAlgorithm = 2
+)
+
+// Enum value maps for KdfAlgorithm.
+var (
+	KdfAlgorithm_name = map[int32]string{
+		0: "KDF_ALGORITHM_NONE",
+		1: "KDF_ALGORITHM_HKDF_SHA256",
+		2: "KDF_ALGORITHM_PBKDF2_SHA256",
+	}
+	KdfAlgorithm_value = map[string]int32{
+		"KDF_ALGORITHM_NONE":          0,
+		"KDF_ALGORITHM_HKDF_SHA256":   1,
+		"KDF_ALGORITHM_PBKDF2_SHA256": 2,
+	}
+)
+
+func (x KdfAlgorithm) Enum() *KdfAlgorithm {
+	p := new(KdfAlgorithm)
+	*p = x
+	return p
+}
+
+func (x KdfAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KdfAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_clip_proto_enumTypes[1].Descriptor()
+}
+
+func (KdfAlgorithm) Type() protoreflect.EnumType {
+	return &file_clip_proto_enumTypes[1]
+}
+
+func (x KdfAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KdfAlgorithm.Descriptor instead.
+func (KdfAlgorithm) EnumDescriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{1}
+}
+
+type Capability int32
+
+const (
+	Capability_CAPABILITY_UNSPECIFIED Capability = 0
+	Capability_CAPABILITY_WINDOWING   Capability = 1
+	Capability_CAPABILITY_CHECKSUMS   Capability = 2
+	Capability_CAPABILITY_RESUME      Capability = 3
+	Capability_CAPABILITY_COMPRESSION Capability = 4
+	Capability_CAPABILITY_ITEMS       Capability = 5
+	Capability_CAPABILITY_ENCRYPTION  Capability = 6
+	Capability_CAPABILITY_EXPIRY      Capability = 7
+)
+
+// Enum value maps for Capability.
+var (
+	Capability_name = map[int32]string{
+		0: "CAPABILITY_UNSPECIFIED",
+		1: "CAPABILITY_WINDOWING",
+		2: "CAPABILITY_CHECKSUMS",
+		3: "CAPABILITY_RESUME",
+		4: "CAPABILITY_COMPRESSION",
+		5: "CAPABILITY_ITEMS",
+		6: "CAPABILITY_ENCRYPTION",
+		7: "CAPABILITY_EXPIRY",
+	}
+	Capability_value = map[string]int32{
+		"CAPABILITY_UNSPECIFIED": 0,
+		"CAPABILITY_WINDOWING":   1,
+		"CAPABILITY_CHECKSUMS":   2,
+		"CAPABILITY_RESUME":      3,
+		"CAPABILITY_COMPRESSION": 4,
+		"CAPABILITY_ITEMS":       5,
+		"CAPABILITY_ENCRYPTION":  6,
+		"CAPABILITY_EXPIRY":      7,
+	}
+)
+
+func (x Capability) Enum() *Capability {
+	p := new(Capability)
+	*p = x
+	return p
+}
+
+func (x Capability) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Capability) Descriptor() protoreflect.EnumDescriptor {
+	return file_clip_proto_enumTypes[2].Descriptor()
+}
+
+func (Capability) Type() protoreflect.EnumType {
+	return &file_clip_proto_enumTypes[2]
+}
+
+func (x Capability) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Capability.Descriptor instead.
+func (Capability) EnumDescriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{2}
+}
+
+type ErrorCode int32
+
+const (
+	ErrorCode_ERROR_CODE_UNSPECIFIED         ErrorCode = 0
+	ErrorCode_ERROR_CODE_INTERNAL            ErrorCode = 1
+	ErrorCode_ERROR_CODE_UNEXPECTED_MESSAGE  ErrorCode = 2
+	ErrorCode_ERROR_CODE_INVALID_CLIP        ErrorCode = 3
+	ErrorCode_ERROR_CODE_BUSY                ErrorCode = 4
+	ErrorCode_ERROR_CODE_DISORDERED          ErrorCode = 5
+	ErrorCode_ERROR_CODE_TOO_LARGE           ErrorCode = 6
+	ErrorCode_ERROR_CODE_UNAUTHORIZED        ErrorCode = 7
+	ErrorCode_ERROR_CODE_RATE_LIMITED        ErrorCode = 8
+	ErrorCode_ERROR_CODE_PROTOCOL_MISMATCH   ErrorCode = 9
+	ErrorCode_ERROR_CODE_CHECKSUM_MISMATCH   ErrorCode = 10
+	ErrorCode_ERROR_CODE_INVALID_RANGE       ErrorCode = 11
+	ErrorCode_ERROR_CODE_FILE_CHANGED        ErrorCode = 12
+	ErrorCode_ERROR_CODE_UPLOAD_ABORTED      ErrorCode = 13
+	ErrorCode_ERROR_CODE_INVALID_CHUNK       ErrorCode = 14
+	ErrorCode_ERROR_CODE_INVALID_VERSION     ErrorCode = 15
+	ErrorCode_ERROR_CODE_INVALID_ITEM        ErrorCode = 16
+	ErrorCode_ERROR_CODE_INVALID_MANIFEST    ErrorCode = 17
+	ErrorCode_ERROR_CODE_INVALID_PATH        ErrorCode = 18
+	ErrorCode_ERROR_CODE_INVALID_ENVELOPE    ErrorCode = 19
+	ErrorCode_ERROR_CODE_ENCRYPTION_REQUIRED ErrorCode = 20
+	ErrorCode_ERROR_CODE_BURN_AFTER_READING  ErrorCode = 21
+	ErrorCode_ERROR_CODE_INVALID_LIFETIME    ErrorCode = 22
+)
+
+// Enum value maps for ErrorCode.
+var (
+	ErrorCode_name = map[int32]string{
+		0:  "ERROR_CODE_UNSPECIFIED",
+		1:  "ERROR_CODE_INTERNAL",
+		2:  "ERROR_CODE_UNEXPECTED_MESSAGE",
+		3:  "ERROR_CODE_INVALID_CLIP",
+		4:  "ERROR_CODE_BUSY",
+		5:  "ERROR_CODE_DISORDERED",
+		6:  "ERROR_CODE_TOO_LARGE",
+		7:  "ERROR_CODE_UNAUTHORIZED",
+		8:  "ERROR_CODE_RATE_LIMITED",
+		9:  "ERROR_CODE_PROTOCOL_MISMATCH",
+		10: "ERROR_CODE_CHECKSUM_MISMATCH",
+		11: "ERROR_CODE_INVALID_RANGE",
+		12: "ERROR_CODE_FILE_CHANGED",
+		13: "ERROR_CODE_UPLOAD_ABORTED",
+		14: "ERROR_CODE_INVALID_CHUNK",
+		15: "ERROR_CODE_INVALID_VERSION",
+		16: "ERROR_CODE_INVALID_ITEM",
+		17: "ERROR_CODE_INVALID_MANIFEST",
+		18: "ERROR_CODE_INVALID_PATH",
+		19: "ERROR_CODE_INVALID_ENVELOPE",
+		20: "ERROR_CODE_ENCRYPTION_REQUIRED",
+		21: "ERROR_CODE_BURN_AFTER_READING",
+		22: "ERROR_CODE_INVALID_LIFETIME",
+	}
+	ErrorCode_value = map[string]int32{
+		"ERROR_CODE_UNSPECIFIED":         0,
+		"ERROR_CODE_INTERNAL":            1,
+		"ERROR_CODE_UNEXPECTED_MESSAGE":  2,
+		"ERROR_CODE_INVALID_CLIP":        3,
+		"ERROR_CODE_BUSY":                4,
+		"ERROR_CODE_DISORDERED":          5,
+		"ERROR_CODE_TOO_LARGE":           6,
+		"ERROR_CODE_UNAUTHORIZED":        7,
+		"ERROR_CODE_RATE_LIMITED":        8,
+		"ERROR_CODE_PROTOCOL_MISMATCH":   9,
+		"ERROR_CODE_CHECKSUM_MISMATCH":   10,
+		"ERROR_CODE_INVALID_RANGE":       11,
+		"ERROR_CODE_FILE_CHANGED":        12,
+		"ERROR_CODE_UPLOAD_ABORTED":      13,
+		"ERROR_CODE_INVALID_CHUNK":       14,
+		"ERROR_CODE_INVALID_VERSION":     15,
+		"ERROR_CODE_INVALID_ITEM":        16,
+		"ERROR_CODE_INVALID_MANIFEST":    17,
+		"ERROR_CODE_INVALID_PATH":        18,
+		"ERROR_CODE_INVALID_ENVELOPE":    19,
+		"ERROR_CODE_ENCRYPTION_REQUIRED": 20,
+		"ERROR_CODE_BURN_AFTER_READING":  21,
+		"ERROR_CODE_INVALID_LIFETIME":    22,
+	}
+)
+
+func (x ErrorCode) Enum() *ErrorCode {
+	p := new(ErrorCode)
+	*p = x
+	return p
+}
+
+func (x ErrorCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_clip_proto_enumTypes[3].Descriptor()
+}
+
+func (ErrorCode) Type() protoreflect.EnumType {
+	return &file_clip_proto_enumTypes[3]
+}
+
+func (x ErrorCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCode.Descriptor instead.
+func (ErrorCode) EnumDescriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{3}
+}
+
 type Message struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Msg:
@@ -31,6 +302,20 @@ type Message struct {
 	//	*Message_NextChunk
 	//	*Message_Ack
 	//	*Message_Err
+	//	*Message_Fetch
+	//	*Message_Hello
+	//	*Message_Welcome
+	//	*Message_Cancel
+	//	*Message_ListVersions
+	//	*Message_Versions
+	//	*Message_RestoreVersion
+	//	*Message_AddItem
+	//	*Message_RemoveItem
+	//	*Message_MoveItem
+	//	*Message_Items
+	//	*Message_Event
+	//	*Message_Consumed
+	//	*Message_Expiry
 	Msg           isMessage_Msg `protobuf_oneof:"msg"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -127,6 +412,132 @@ func (x *Message) GetErr() *Error {
 	return nil
 }
 
+func (x *Message) GetFetch() *Fetch {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Fetch); ok {
+			return x.Fetch
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetHello() *Hello {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Hello); ok {
+			return x.Hello
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetWelcome() *Welcome {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Welcome); ok {
+			return x.Welcome
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetCancel() *Cancel {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Cancel); ok {
+			return x.Cancel
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetListVersions() *ListVersions {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_ListVersions); ok {
+			return x.ListVersions
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetVersions() *Versions {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Versions); ok {
+			return x.Versions
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetRestoreVersion() *RestoreVersion {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_RestoreVersion); ok {
+			return x.RestoreVersion
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetAddItem() *AddItem {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_AddItem); ok {
+			return x.AddItem
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetRemoveItem() *RemoveItem {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_RemoveItem); ok {
+			return x.RemoveItem
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetMoveItem() *MoveItem {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_MoveItem); ok {
+			return x.MoveItem
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetItems() *Items {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Items); ok {
+			return x.Items
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetEvent() *Event {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Event); ok {
+			return x.Event
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetConsumed() *Consumed {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Consumed); ok {
+			return x.Consumed
+		}
+	}
+	return nil
+}
+
+func (x *Message) GetExpiry() *Expiry {
+	if x != nil {
+		if x, ok := x.Msg.(*Message_Expiry); ok {
+			return x.Expiry
+		}
+	}
+	return nil
+}
+
 type isMessage_Msg interface {
 	isMessage_Msg()
 }
@@ -155,6 +566,62 @@ type Message_Err struct {
 	Err *Error `protobuf:"bytes,6,opt,name=err,proto3,oneof"`
 }
 
+type Message_Fetch struct {
+	Fetch *Fetch `protobuf:"bytes,7,opt,name=fetch,proto3,oneof"`
+}
+
+type Message_Hello struct {
+	Hello *Hello `protobuf:"bytes,8,opt,name=hello,proto3,oneof"`
+}
+
+type Message_Welcome struct {
+	Welcome *Welcome `protobuf:"bytes,9,opt,name=welcome,proto3,oneof"`
+}
+
+type Message_Cancel struct {
+	Cancel *Cancel `protobuf:"bytes,10,opt,name=cancel,proto3,oneof"`
+}
+
+type Message_ListVersions struct {
+	ListVersions *ListVersions `protobuf:"bytes,11,opt,name=listVersions,proto3,oneof"`
+}
+
+type Message_Versions struct {
+	Versions *Versions `protobuf:"bytes,12,opt,name=versions,proto3,oneof"`
+}
+
+type Message_RestoreVersion struct {
+	RestoreVersion *RestoreVersion `protobuf:"bytes,13,opt,name=restoreVersion,proto3,oneof"`
+}
+
+type Message_AddItem struct {
+	AddItem *AddItem `protobuf:"bytes,14,opt,name=addItem,proto3,oneof"`
+}
+
+type Message_RemoveItem struct {
+	RemoveItem *RemoveItem `protobuf:"bytes,15,opt,name=removeItem,proto3,oneof"`
+}
+
+type Message_MoveItem struct {
+	MoveItem *MoveItem `protobuf:"bytes,16,opt,name=moveItem,proto3,oneof"`
+}
+
+type Message_Items struct {
+	Items *Items `protobuf:"bytes,17,opt,name=items,proto3,oneof"`
+}
+
+type Message_Event struct {
+	Event *Event `protobuf:"bytes,18,opt,name=event,proto3,oneof"`
+}
+
+type Message_Consumed struct {
+	Consumed *Consumed `protobuf:"bytes,19,opt,name=consumed,proto3,oneof"`
+}
+
+type Message_Expiry struct {
+	Expiry *Expiry `protobuf:"bytes,20,opt,name=expiry,proto3,oneof"`
+}
+
 func (*Message_Text) isMessage_Msg() {}
 
 func (*Message_Hdr) isMessage_Msg() {}
@@ -167,9 +634,40 @@ func (*Message_Ack) isMessage_Msg() {}
 
 func (*Message_Err) isMessage_Msg() {}
 
+func (*Message_Fetch) isMessage_Msg() {}
+
+func (*Message_Hello) isMessage_Msg() {}
+
+func (*Message_Welcome) isMessage_Msg() {}
+
+func (*Message_Cancel) isMessage_Msg() {}
+
+func (*Message_ListVersions) isMessage_Msg() {}
+
+func (*Message_Versions) isMessage_Msg() {}
+
+func (*Message_RestoreVersion) isMessage_Msg() {}
+
+func (*Message_AddItem) isMessage_Msg() {}
+
+func (*Message_RemoveItem) isMessage_Msg() {}
+
+func (*Message_MoveItem) isMessage_Msg() {}
+
+func (*Message_Items) isMessage_Msg() {}
+
+func (*Message_Event) isMessage_Msg() {}
+
+func (*Message_Consumed) isMessage_Msg() {}
+
+func (*Message_Expiry) isMessage_Msg() {}
+
 type Text struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Data          string                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Item          string                 `protobuf:"bytes,2,opt,name=item,proto3" json:"item,omitempty"`
+	Ciphertext    []byte                 `protobuf:"bytes,3,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"` // replaces data in encrypted clips
+	Envelope      *Envelope              `protobuf:"bytes,4,opt,name=envelope,proto3" json:"envelope,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -211,11 +709,38 @@ func (x *Text) GetData() string {
 	return ""
 }
 
+func (x *Text) GetItem() string {
+	if x != nil {
+		return x.Item
+	}
+	return ""
+}
+
+func (x *Text) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *Text) GetEnvelope() *Envelope {
+	if x != nil {
+		return x.Envelope
+	}
+	return nil
+}
+
 type FileHeader struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
 	ContentType   string                 `protobuf:"bytes,2,opt,name=contentType,proto3" json:"contentType,omitempty"`
 	NumChunks     int32                  `protobuf:"varint,3,opt,name=numChunks,proto3" json:"numChunks,omitempty"`
+	Session       string                 `protobuf:"bytes,4,opt,name=session,proto3" json:"session,omitempty"`
+	Window        int32                  `protobuf:"varint,5,opt,name=window,proto3" json:"window,omitempty"`
+	Digest        []byte                 `protobuf:"bytes,6,opt,name=digest,proto3" json:"digest,omitempty"`
+	Item          string                 `protobuf:"bytes,7,opt,name=item,proto3" json:"item,omitempty"`
+	Manifest      *Manifest              `protobuf:"bytes,8,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	Envelope      *Envelope              `protobuf:"bytes,9,opt,name=envelope,proto3" json:"envelope,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -271,31 +796,1169 @@ func (x *FileHeader) GetNumChunks() int32 {
 	return 0
 }
 
-type Chunk struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *FileHeader) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
 }
 
-func (x *Chunk) Reset() {
-	*x = Chunk{}
-	mi := &file_clip_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *FileHeader) GetWindow() int32 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
 }
 
-func (x *Chunk) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *FileHeader) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
 }
 
-func (*Chunk) ProtoMessage() {}
-
-func (x *Chunk) ProtoReflect() protoreflect.Message {
-	mi := &file_clip_proto_msgTypes[3]
+func (x *FileHeader) GetItem() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		return x.Item
+	}
+	return ""
+}
+
+func (x *FileHeader) GetManifest() *Manifest {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+func (x *FileHeader) GetEnvelope() *Envelope {
+	if x != nil {
+		return x.Envelope
+	}
+	return nil
+}
+
+// Envelope tells the clients of an encrypted clip how a text or a file was sealed with the key kept
+// in the fragment of the URL of the clip, which never reaches the server. The key of the content is
+// derived from that key with the kdf. A text is sealed with the nonce, the metadata of a file, such as
+// its name, with the nonce, and chunk i with the nonce whose last 4 bytes are xored with i+1 big endian.
+type Envelope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cipher        Cipher                 `protobuf:"varint,1,opt,name=cipher,proto3,enum=clip.Cipher" json:"cipher,omitempty"`
+	Nonce         []byte                 `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Kdf           *Kdf                   `protobuf:"bytes,3,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	Metadata      []byte                 `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	mi := &file_clip_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Envelope) ProtoMessage() {}
+
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Envelope) GetCipher() Cipher {
+	if x != nil {
+		return x.Cipher
+	}
+	return Cipher_CIPHER_UNSPECIFIED
+}
+
+func (x *Envelope) GetNonce() []byte {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *Envelope) GetKdf() *Kdf {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *Envelope) GetMetadata() []byte {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type Kdf struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     KdfAlgorithm           `protobuf:"varint,1,opt,name=algorithm,proto3,enum=clip.KdfAlgorithm" json:"algorithm,omitempty"`
+	Salt          []byte                 `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	Iterations    uint32                 `protobuf:"varint,3,opt,name=iterations,proto3" json:"iterations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Kdf) Reset() {
+	*x = Kdf{}
+	mi := &file_clip_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Kdf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Kdf) ProtoMessage() {}
+
+func (x *Kdf) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Kdf.ProtoReflect.Descriptor instead.
+func (*Kdf) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Kdf) GetAlgorithm() KdfAlgorithm {
+	if x != nil {
+		return x.Algorithm
+	}
+	return KdfAlgorithm_KDF_ALGORITHM_NONE
+}
+
+func (x *Kdf) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *Kdf) GetIterations() uint32 {
+	if x != nil {
+		return x.Iterations
+	}
+	return 0
+}
+
+type Entry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Mode          uint32                 `protobuf:"varint,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	Dir           bool                   `protobuf:"varint,4,opt,name=dir,proto3" json:"dir,omitempty"`
+	Start         int32                  `protobuf:"varint,5,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,6,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Entry) Reset() {
+	*x = Entry{}
+	mi := &file_clip_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry) ProtoMessage() {}
+
+func (x *Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry.ProtoReflect.Descriptor instead.
+func (*Entry) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Entry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Entry) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Entry) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *Entry) GetDir() bool {
+	if x != nil {
+		return x.Dir
+	}
+	return false
+}
+
+func (x *Entry) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Entry) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type Manifest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*Entry               `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Manifest) Reset() {
+	*x = Manifest{}
+	mi := &file_clip_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Manifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Manifest) ProtoMessage() {}
+
+func (x *Manifest) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Manifest.ProtoReflect.Descriptor instead.
+func (*Manifest) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Manifest) GetEntries() []*Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type Chunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Hash          []byte                 `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	Compressed    bool                   `protobuf:"varint,4,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Chunk) Reset() {
+	*x = Chunk{}
+	mi := &file_clip_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Chunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chunk) ProtoMessage() {}
+
+func (x *Chunk) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Chunk) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Chunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Chunk) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *Chunk) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
+type NextChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Credits       int32                  `protobuf:"varint,2,opt,name=credits,proto3" json:"credits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NextChunk) Reset() {
+	*x = NextChunk{}
+	mi := &file_clip_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NextChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NextChunk) ProtoMessage() {}
+
+func (x *NextChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NextChunk.ProtoReflect.Descriptor instead.
+func (*NextChunk) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *NextChunk) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *NextChunk) GetCredits() int32 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+type Fetch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       string                 `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Start         int32                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	Item          string                 `protobuf:"bytes,4,opt,name=item,proto3" json:"item,omitempty"`
+	Path          string                 `protobuf:"bytes,5,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Fetch) Reset() {
+	*x = Fetch{}
+	mi := &file_clip_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fetch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fetch) ProtoMessage() {}
+
+func (x *Fetch) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fetch.ProtoReflect.Descriptor instead.
+func (*Fetch) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Fetch) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+func (x *Fetch) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Fetch) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *Fetch) GetItem() string {
+	if x != nil {
+		return x.Item
+	}
+	return ""
+}
+
+func (x *Fetch) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type Ack struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Ack) Reset() {
+	*x = Ack{}
+	mi := &file_clip_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ack) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ack) ProtoMessage() {}
+
+func (x *Ack) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
+func (*Ack) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{10}
+}
+
+type Cancel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Session       string                 `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cancel) Reset() {
+	*x = Cancel{}
+	mi := &file_clip_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cancel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cancel) ProtoMessage() {}
+
+func (x *Cancel) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cancel.ProtoReflect.Descriptor instead.
+func (*Cancel) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Cancel) GetSession() string {
+	if x != nil {
+		return x.Session
+	}
+	return ""
+}
+
+type ListVersions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersions) Reset() {
+	*x = ListVersions{}
+	mi := &file_clip_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersions) ProtoMessage() {}
+
+func (x *ListVersions) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersions.ProtoReflect.Descriptor instead.
+func (*ListVersions) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{12}
+}
+
+type Version struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Timestamp int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Author    string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*Version_Text
+	//	*Version_Hdr
+	Content       isVersion_Content `protobuf_oneof:"content"`
+	Items         []*Item           `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	mi := &file_clip_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Version) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Version) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Version) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Version) GetContent() isVersion_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *Version) GetText() *Text {
+	if x != nil {
+		if x, ok := x.Content.(*Version_Text); ok {
+			return x.Text
+		}
+	}
+	return nil
+}
+
+func (x *Version) GetHdr() *FileHeader {
+	if x != nil {
+		if x, ok := x.Content.(*Version_Hdr); ok {
+			return x.Hdr
+		}
+	}
+	return nil
+}
+
+func (x *Version) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type isVersion_Content interface {
+	isVersion_Content()
+}
+
+type Version_Text struct {
+	Text *Text `protobuf:"bytes,4,opt,name=text,proto3,oneof"`
+}
+
+type Version_Hdr struct {
+	Hdr *FileHeader `protobuf:"bytes,5,opt,name=hdr,proto3,oneof"`
+}
+
+func (*Version_Text) isVersion_Content() {}
+
+func (*Version_Hdr) isVersion_Content() {}
+
+type Versions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Versions      []*Version             `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`
+	Current       int32                  `protobuf:"varint,2,opt,name=current,proto3" json:"current,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Versions) Reset() {
+	*x = Versions{}
+	mi := &file_clip_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Versions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Versions) ProtoMessage() {}
+
+func (x *Versions) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Versions.ProtoReflect.Descriptor instead.
+func (*Versions) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Versions) GetVersions() []*Version {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *Versions) GetCurrent() int32 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+type RestoreVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreVersion) Reset() {
+	*x = RestoreVersion{}
+	mi := &file_clip_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreVersion) ProtoMessage() {}
+
+func (x *RestoreVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreVersion.ProtoReflect.Descriptor instead.
+func (*RestoreVersion) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RestoreVersion) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type Item struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*Item_Text
+	//	*Item_Hdr
+	Content       isItem_Content `protobuf_oneof:"content"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Item) Reset() {
+	*x = Item{}
+	mi := &file_clip_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Item) ProtoMessage() {}
+
+func (x *Item) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Item.ProtoReflect.Descriptor instead.
+func (*Item) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Item) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Item) GetContent() isItem_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *Item) GetText() *Text {
+	if x != nil {
+		if x, ok := x.Content.(*Item_Text); ok {
+			return x.Text
+		}
+	}
+	return nil
+}
+
+func (x *Item) GetHdr() *FileHeader {
+	if x != nil {
+		if x, ok := x.Content.(*Item_Hdr); ok {
+			return x.Hdr
+		}
+	}
+	return nil
+}
+
+type isItem_Content interface {
+	isItem_Content()
+}
+
+type Item_Text struct {
+	Text *Text `protobuf:"bytes,2,opt,name=text,proto3,oneof"`
+}
+
+type Item_Hdr struct {
+	Hdr *FileHeader `protobuf:"bytes,3,opt,name=hdr,proto3,oneof"`
+}
+
+func (*Item_Text) isItem_Content() {}
+
+func (*Item_Hdr) isItem_Content() {}
+
+type Items struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Item                `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Items) Reset() {
+	*x = Items{}
+	mi := &file_clip_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Items) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Items) ProtoMessage() {}
+
+func (x *Items) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Items.ProtoReflect.Descriptor instead.
+func (*Items) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Items) GetItems() []*Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type AddItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Content:
+	//
+	//	*AddItem_Text
+	//	*AddItem_Hdr
+	Content       isAddItem_Content `protobuf_oneof:"content"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddItem) Reset() {
+	*x = AddItem{}
+	mi := &file_clip_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddItem) ProtoMessage() {}
+
+func (x *AddItem) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddItem.ProtoReflect.Descriptor instead.
+func (*AddItem) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AddItem) GetContent() isAddItem_Content {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *AddItem) GetText() *Text {
+	if x != nil {
+		if x, ok := x.Content.(*AddItem_Text); ok {
+			return x.Text
+		}
+	}
+	return nil
+}
+
+func (x *AddItem) GetHdr() *FileHeader {
+	if x != nil {
+		if x, ok := x.Content.(*AddItem_Hdr); ok {
+			return x.Hdr
+		}
+	}
+	return nil
+}
+
+type isAddItem_Content interface {
+	isAddItem_Content()
+}
+
+type AddItem_Text struct {
+	Text *Text `protobuf:"bytes,1,opt,name=text,proto3,oneof"`
+}
+
+type AddItem_Hdr struct {
+	Hdr *FileHeader `protobuf:"bytes,2,opt,name=hdr,proto3,oneof"`
+}
+
+func (*AddItem_Text) isAddItem_Content() {}
+
+func (*AddItem_Hdr) isAddItem_Content() {}
+
+type RemoveItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveItem) Reset() {
+	*x = RemoveItem{}
+	mi := &file_clip_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveItem) ProtoMessage() {}
+
+func (x *RemoveItem) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveItem.ProtoReflect.Descriptor instead.
+func (*RemoveItem) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RemoveItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type MoveItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Position      int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveItem) Reset() {
+	*x = MoveItem{}
+	mi := &file_clip_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveItem) ProtoMessage() {}
+
+func (x *MoveItem) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveItem.ProtoReflect.Descriptor instead.
+func (*MoveItem) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MoveItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MoveItem) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Author        string                 `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Items         []*Item                `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_clip_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
@@ -304,46 +1967,63 @@ func (x *Chunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
-func (*Chunk) Descriptor() ([]byte, []int) {
-	return file_clip_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *Chunk) GetIndex() int32 {
+func (x *Event) GetVersion() int32 {
 	if x != nil {
-		return x.Index
+		return x.Version
 	}
 	return 0
 }
 
-func (x *Chunk) GetData() []byte {
+func (x *Event) GetAuthor() string {
 	if x != nil {
-		return x.Data
+		return x.Author
+	}
+	return ""
+}
+
+func (x *Event) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Event) GetItems() []*Item {
+	if x != nil {
+		return x.Items
 	}
 	return nil
 }
 
-type NextChunk struct {
+type Hello struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities  []Capability           `protobuf:"varint,2,rep,packed,name=capabilities,proto3,enum=clip.Capability" json:"capabilities,omitempty"`
+	ClientId      string                 `protobuf:"bytes,3,opt,name=clientId,proto3" json:"clientId,omitempty"` // random id kept across reconnections, which tells uploaders apart
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *NextChunk) Reset() {
-	*x = NextChunk{}
-	mi := &file_clip_proto_msgTypes[4]
+func (x *Hello) Reset() {
+	*x = Hello{}
+	mi := &file_clip_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *NextChunk) String() string {
+func (x *Hello) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NextChunk) ProtoMessage() {}
+func (*Hello) ProtoMessage() {}
 
-func (x *NextChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_clip_proto_msgTypes[4]
+func (x *Hello) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -354,32 +2034,60 @@ func (x *NextChunk) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NextChunk.ProtoReflect.Descriptor instead.
-func (*NextChunk) Descriptor() ([]byte, []int) {
-	return file_clip_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use Hello.ProtoReflect.Descriptor instead.
+func (*Hello) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{22}
 }
 
-type Ack struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Hello) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
 }
 
-func (x *Ack) Reset() {
-	*x = Ack{}
-	mi := &file_clip_proto_msgTypes[5]
+func (x *Hello) GetCapabilities() []Capability {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *Hello) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+type Welcome struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Version          int32                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Capabilities     []Capability           `protobuf:"varint,2,rep,packed,name=capabilities,proto3,enum=clip.Capability" json:"capabilities,omitempty"`
+	Encrypted        bool                   `protobuf:"varint,3,opt,name=encrypted,proto3" json:"encrypted,omitempty"`
+	ReadOnly         bool                   `protobuf:"varint,4,opt,name=readOnly,proto3" json:"readOnly,omitempty"`
+	BurnAfterReading bool                   `protobuf:"varint,5,opt,name=burnAfterReading,proto3" json:"burnAfterReading,omitempty"`
+	Ttl              int64                  `protobuf:"varint,6,opt,name=ttl,proto3" json:"ttl,omitempty"`         // milliseconds the clip lives on once nobody is connected
+	Expires          int64                  `protobuf:"varint,7,opt,name=expires,proto3" json:"expires,omitempty"` // unix milliseconds at which the clip ends, 0 for never
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Welcome) Reset() {
+	*x = Welcome{}
+	mi := &file_clip_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Ack) String() string {
+func (x *Welcome) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Ack) ProtoMessage() {}
+func (*Welcome) ProtoMessage() {}
 
-func (x *Ack) ProtoReflect() protoreflect.Message {
-	mi := &file_clip_proto_msgTypes[5]
+func (x *Welcome) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -390,22 +2098,76 @@ func (x *Ack) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Ack.ProtoReflect.Descriptor instead.
-func (*Ack) Descriptor() ([]byte, []int) {
-	return file_clip_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Welcome.ProtoReflect.Descriptor instead.
+func (*Welcome) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Welcome) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Welcome) GetCapabilities() []Capability {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *Welcome) GetEncrypted() bool {
+	if x != nil {
+		return x.Encrypted
+	}
+	return false
+}
+
+func (x *Welcome) GetReadOnly() bool {
+	if x != nil {
+		return x.ReadOnly
+	}
+	return false
+}
+
+func (x *Welcome) GetBurnAfterReading() bool {
+	if x != nil {
+		return x.BurnAfterReading
+	}
+	return false
+}
+
+func (x *Welcome) GetTtl() int64 {
+	if x != nil {
+		return x.Ttl
+	}
+	return 0
+}
+
+func (x *Welcome) GetExpires() int64 {
+	if x != nil {
+		return x.Expires
+	}
+	return 0
 }
 
 type Error struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Fatal         bool                   `protobuf:"varint,1,opt,name=fatal,proto3" json:"fatal,omitempty"`
-	Desc          string                 `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Fatal bool                   `protobuf:"varint,1,opt,name=fatal,proto3" json:"fatal,omitempty"`
+	Desc  string                 `protobuf:"bytes,2,opt,name=desc,proto3" json:"desc,omitempty"`
+	Code  ErrorCode              `protobuf:"varint,4,opt,name=code,proto3,enum=clip.ErrorCode" json:"code,omitempty"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*Error_Busy
+	Kind          isError_Kind `protobuf_oneof:"kind"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Error) Reset() {
 	*x = Error{}
-	mi := &file_clip_proto_msgTypes[6]
+	mi := &file_clip_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -417,7 +2179,7 @@ func (x *Error) String() string {
 func (*Error) ProtoMessage() {}
 
 func (x *Error) ProtoReflect() protoreflect.Message {
-	mi := &file_clip_proto_msgTypes[6]
+	mi := &file_clip_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -430,7 +2192,7 @@ func (x *Error) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Error.ProtoReflect.Descriptor instead.
 func (*Error) Descriptor() ([]byte, []int) {
-	return file_clip_proto_rawDescGZIP(), []int{6}
+	return file_clip_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *Error) GetFatal() bool {
@@ -447,11 +2209,203 @@ func (x *Error) GetDesc() string {
 	return ""
 }
 
+func (x *Error) GetCode() ErrorCode {
+	if x != nil {
+		return x.Code
+	}
+	return ErrorCode_ERROR_CODE_UNSPECIFIED
+}
+
+func (x *Error) GetKind() isError_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *Error) GetBusy() *Busy {
+	if x != nil {
+		if x, ok := x.Kind.(*Error_Busy); ok {
+			return x.Busy
+		}
+	}
+	return nil
+}
+
+type isError_Kind interface {
+	isError_Kind()
+}
+
+type Error_Busy struct {
+	Busy *Busy `protobuf:"bytes,3,opt,name=busy,proto3,oneof"`
+}
+
+func (*Error_Busy) isError_Kind() {}
+
+type Busy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uploader      string                 `protobuf:"bytes,1,opt,name=uploader,proto3" json:"uploader,omitempty"`
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"`
+	Received      int32                  `protobuf:"varint,3,opt,name=received,proto3" json:"received,omitempty"`
+	NumChunks     int32                  `protobuf:"varint,4,opt,name=numChunks,proto3" json:"numChunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Busy) Reset() {
+	*x = Busy{}
+	mi := &file_clip_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Busy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Busy) ProtoMessage() {}
+
+func (x *Busy) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Busy.ProtoReflect.Descriptor instead.
+func (*Busy) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Busy) GetUploader() string {
+	if x != nil {
+		return x.Uploader
+	}
+	return ""
+}
+
+func (x *Busy) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Busy) GetReceived() int32 {
+	if x != nil {
+		return x.Received
+	}
+	return 0
+}
+
+func (x *Busy) GetNumChunks() int32 {
+	if x != nil {
+		return x.NumChunks
+	}
+	return 0
+}
+
+// Consumed tells the uploader of an item of a burn after reading clip that another client has received it.
+// The clip ends right after.
+type Consumed struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          string                 `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Consumed) Reset() {
+	*x = Consumed{}
+	mi := &file_clip_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Consumed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Consumed) ProtoMessage() {}
+
+func (x *Consumed) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Consumed.ProtoReflect.Descriptor instead.
+func (*Consumed) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Consumed) GetItem() string {
+	if x != nil {
+		return x.Item
+	}
+	return ""
+}
+
+// Expiry warns clients which agreed on CAPABILITY_EXPIRY that the clip is about to end.
+type Expiry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // unix milliseconds at which the clip ends, 0 once it no longer does soon
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Expiry) Reset() {
+	*x = Expiry{}
+	mi := &file_clip_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Expiry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Expiry) ProtoMessage() {}
+
+func (x *Expiry) ProtoReflect() protoreflect.Message {
+	mi := &file_clip_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Expiry.ProtoReflect.Descriptor instead.
+func (*Expiry) Descriptor() ([]byte, []int) {
+	return file_clip_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Expiry) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
 var File_clip_proto protoreflect.FileDescriptor
 
 var file_clip_proto_rawDesc = string([]byte{
 	0x0a, 0x0a, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x04, 0x63, 0x6c,
-	0x69, 0x70, 0x22, 0xee, 0x01, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20,
+	0x69, 0x70, 0x22, 0xe0, 0x06, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x20,
 	0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x63,
 	0x6c, 0x69, 0x70, 0x2e, 0x54, 0x65, 0x78, 0x74, 0x48, 0x00, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74,
 	0x12, 0x24, 0x0a, 0x03, 0x68, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
@@ -465,25 +2419,281 @@ var file_clip_proto_rawDesc = string([]byte{
 	0x61, 0x63, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x63, 0x6c, 0x69, 0x70,
 	0x2e, 0x41, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x12, 0x1f, 0x0a, 0x03, 0x65,
 	0x72, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e,
-	0x45, 0x72, 0x72, 0x6f, 0x72, 0x48, 0x00, 0x52, 0x03, 0x65, 0x72, 0x72, 0x42, 0x05, 0x0a, 0x03,
-	0x6d, 0x73, 0x67, 0x22, 0x1a, 0x0a, 0x04, 0x54, 0x65, 0x78, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64,
-	0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22,
-	0x68, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x0a,
-	0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e,
-	0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e,
-	0x75, 0x6d, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
-	0x6e, 0x75, 0x6d, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x22, 0x31, 0x0a, 0x05, 0x43, 0x68, 0x75,
-	0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x0b, 0x0a, 0x09,
-	0x4e, 0x65, 0x78, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x05, 0x0a, 0x03, 0x41, 0x63, 0x6b,
-	0x22, 0x31, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x61, 0x74,
-	0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x61, 0x74, 0x61, 0x6c, 0x12,
-	0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64,
-	0x65, 0x73, 0x63, 0x42, 0x09, 0x5a, 0x07, 0x70, 0x62, 0x2f, 0x63, 0x6c, 0x69, 0x70, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x48, 0x00, 0x52, 0x03, 0x65, 0x72, 0x72, 0x12, 0x23, 0x0a, 0x05,
+	0x66, 0x65, 0x74, 0x63, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x63, 0x6c,
+	0x69, 0x70, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x48, 0x00, 0x52, 0x05, 0x66, 0x65, 0x74, 0x63,
+	0x68, 0x12, 0x23, 0x0a, 0x05, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0b, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x48, 0x00, 0x52,
+	0x05, 0x68, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x29, 0x0a, 0x07, 0x77, 0x65, 0x6c, 0x63, 0x6f, 0x6d,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x57,
+	0x65, 0x6c, 0x63, 0x6f, 0x6d, 0x65, 0x48, 0x00, 0x52, 0x07, 0x77, 0x65, 0x6c, 0x63, 0x6f, 0x6d,
+	0x65, 0x12, 0x26, 0x0a, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0c, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x48,
+	0x00, 0x52, 0x06, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x38, 0x0a, 0x0c, 0x6c, 0x69, 0x73,
+	0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x0c, 0x6c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x12, 0x2c, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x48, 0x00, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x3e, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x6c, 0x69, 0x70,
+	0x2e, 0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x48,
+	0x00, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x12, 0x29, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x41, 0x64, 0x64, 0x49, 0x74, 0x65,
+	0x6d, 0x48, 0x00, 0x52, 0x07, 0x61, 0x64, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x32, 0x0a, 0x0a,
+	0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x48, 0x00, 0x52, 0x0a, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d,
+	0x12, 0x2c, 0x0a, 0x08, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x18, 0x10, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x4d, 0x6f, 0x76, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x23,
+	0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e,
+	0x63, 0x6c, 0x69, 0x70, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x48, 0x00, 0x52, 0x05, 0x69, 0x74,
+	0x65, 0x6d, 0x73, 0x12, 0x23, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x12, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48,
+	0x00, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x08, 0x63, 0x6f, 0x6e, 0x73,
+	0x75, 0x6d, 0x65, 0x64, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6c, 0x69,
+	0x70, 0x2e, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x48, 0x00, 0x52, 0x08, 0x63, 0x6f,
+	0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79,
+	0x18, 0x14, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x45, 0x78,
+	0x70, 0x69, 0x72, 0x79, 0x48, 0x00, 0x52, 0x06, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x42, 0x05,
+	0x0a, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x7a, 0x0a, 0x04, 0x54, 0x65, 0x78, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x69, 0x74, 0x65, 0x6d, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x74,
+	0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x63, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x74, 0x65, 0x78, 0x74, 0x12, 0x2a, 0x0a, 0x08, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x45,
+	0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x52, 0x08, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70,
+	0x65, 0x22, 0x9e, 0x02, 0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x6e, 0x75, 0x6d, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x09, 0x6e, 0x75, 0x6d, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x12, 0x2a, 0x0a, 0x08, 0x6d, 0x61,
+	0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63,
+	0x6c, 0x69, 0x70, 0x2e, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x08, 0x6d, 0x61,
+	0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x08, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f,
+	0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e,
+	0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x52, 0x08, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f,
+	0x70, 0x65, 0x22, 0x7f, 0x0a, 0x08, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x24,
+	0x0a, 0x06, 0x63, 0x69, 0x70, 0x68, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c,
+	0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x52, 0x06, 0x63, 0x69,
+	0x70, 0x68, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x03, 0x6b, 0x64,
+	0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x4b,
+	0x64, 0x66, 0x52, 0x03, 0x6b, 0x64, 0x66, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x6b, 0x0a, 0x03, 0x4b, 0x64, 0x66, 0x12, 0x30, 0x0a, 0x09, 0x61, 0x6c,
+	0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e,
+	0x63, 0x6c, 0x69, 0x70, 0x2e, 0x4b, 0x64, 0x66, 0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68,
+	0x6d, 0x52, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x61, 0x6c, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x61, 0x6c, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x69, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x69, 0x74, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x22, 0x7d, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
+	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x69, 0x7a,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x64, 0x69, 0x72, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x03, 0x64, 0x69, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x65, 0x6e, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22,
+	0x31, 0x0a, 0x08, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x07, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x63,
+	0x6c, 0x69, 0x70, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69,
+	0x65, 0x73, 0x22, 0x65, 0x0a, 0x05, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63,
+	0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x65, 0x64, 0x22, 0x3b, 0x0a, 0x09, 0x4e, 0x65, 0x78,
+	0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x63,
+	0x72, 0x65, 0x64, 0x69, 0x74, 0x73, 0x22, 0x71, 0x0a, 0x05, 0x46, 0x65, 0x74, 0x63, 0x68, 0x12,
+	0x18, 0x0a, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x65, 0x6e,
+	0x64, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x69, 0x74, 0x65, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x05, 0x0a, 0x03, 0x41, 0x63, 0x6b,
+	0x22, 0x22, 0x0a, 0x06, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x22, 0x0e, 0x0a, 0x0c, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x73, 0x22, 0xc4, 0x01, 0x0a, 0x07, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16,
+	0x0a, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x20, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x54, 0x65, 0x78, 0x74,
+	0x48, 0x00, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x24, 0x0a, 0x03, 0x68, 0x64, 0x72, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x00, 0x52, 0x03, 0x68, 0x64, 0x72, 0x12, 0x20,
+	0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x63, 0x6c, 0x69, 0x70, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73,
+	0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x4f, 0x0a, 0x08, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x29, 0x0a, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6c, 0x69, 0x70,
+	0x2e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x22, 0x20, 0x0a, 0x0e,
+	0x52, 0x65, 0x73, 0x74, 0x6f, 0x72, 0x65, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x69, 0x64, 0x22, 0x69,
+	0x0a, 0x04, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x20, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x54, 0x65, 0x78, 0x74,
+	0x48, 0x00, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x24, 0x0a, 0x03, 0x68, 0x64, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x48, 0x00, 0x52, 0x03, 0x68, 0x64, 0x72, 0x42, 0x09,
+	0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x29, 0x0a, 0x05, 0x49, 0x74, 0x65,
+	0x6d, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x0a, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x22, 0x5c, 0x0a, 0x07, 0x41, 0x64, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x12,
+	0x20, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x63, 0x6c, 0x69, 0x70, 0x2e, 0x54, 0x65, 0x78, 0x74, 0x48, 0x00, 0x52, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x12, 0x24, 0x0a, 0x03, 0x68, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x48, 0x00, 0x52, 0x03, 0x68, 0x64, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x22, 0x1c, 0x0a, 0x0a, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x36, 0x0a, 0x08, 0x4d, 0x6f, 0x76, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x79, 0x0a, 0x05, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x61,
+	0x75, 0x74, 0x68, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x75, 0x74,
+	0x68, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x20, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0a, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74,
+	0x65, 0x6d, 0x73, 0x22, 0x73, 0x0a, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x18, 0x0a, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x34, 0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x63,
+	0x6c, 0x69, 0x70, 0x2e, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x0c,
+	0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x22, 0xeb, 0x01, 0x0a, 0x07, 0x57, 0x65, 0x6c,
+	0x63, 0x6f, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x34,
+	0x0a, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e, 0x43, 0x61, 0x70, 0x61,
+	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x0c, 0x63, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x65,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x61, 0x64, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x2a,
+	0x0a, 0x10, 0x62, 0x75, 0x72, 0x6e, 0x41, 0x66, 0x74, 0x65, 0x72, 0x52, 0x65, 0x61, 0x64, 0x69,
+	0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x62, 0x75, 0x72, 0x6e, 0x41, 0x66,
+	0x74, 0x65, 0x72, 0x52, 0x65, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x74,
+	0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x74, 0x74, 0x6c, 0x12, 0x18, 0x0a, 0x07,
+	0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x65,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x22, 0x80, 0x01, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x12, 0x14, 0x0a, 0x05, 0x66, 0x61, 0x74, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x66, 0x61, 0x74, 0x61, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x64, 0x65, 0x73, 0x63, 0x12, 0x23, 0x0a, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x63, 0x6c, 0x69, 0x70, 0x2e,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12,
+	0x20, 0x0a, 0x04, 0x62, 0x75, 0x73, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0a, 0x2e,
+	0x63, 0x6c, 0x69, 0x70, 0x2e, 0x42, 0x75, 0x73, 0x79, 0x48, 0x00, 0x52, 0x04, 0x62, 0x75, 0x73,
+	0x79, 0x42, 0x06, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0x78, 0x0a, 0x04, 0x42, 0x75, 0x73,
+	0x79, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x72, 0x65, 0x63,
+	0x65, 0x69, 0x76, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x75, 0x6d, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6e, 0x75, 0x6d, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x73, 0x22, 0x1e, 0x0a, 0x08, 0x43, 0x6f, 0x6e, 0x73, 0x75, 0x6d, 0x65, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x69, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69,
+	0x74, 0x65, 0x6d, 0x22, 0x26, 0x0a, 0x06, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x1c, 0x0a,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2a, 0x57, 0x0a, 0x06, 0x43,
+	0x69, 0x70, 0x68, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x12, 0x43, 0x49, 0x50, 0x48, 0x45, 0x52, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x16, 0x0a,
+	0x12, 0x43, 0x49, 0x50, 0x48, 0x45, 0x52, 0x5f, 0x41, 0x45, 0x53, 0x5f, 0x32, 0x35, 0x36, 0x5f,
+	0x47, 0x43, 0x4d, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x43, 0x49, 0x50, 0x48, 0x45, 0x52, 0x5f,
+	0x58, 0x43, 0x48, 0x41, 0x43, 0x48, 0x41, 0x32, 0x30, 0x5f, 0x50, 0x4f, 0x4c, 0x59, 0x31, 0x33,
+	0x30, 0x35, 0x10, 0x02, 0x2a, 0x66, 0x0a, 0x0c, 0x4b, 0x64, 0x66, 0x41, 0x6c, 0x67, 0x6f, 0x72,
+	0x69, 0x74, 0x68, 0x6d, 0x12, 0x16, 0x0a, 0x12, 0x4b, 0x44, 0x46, 0x5f, 0x41, 0x4c, 0x47, 0x4f,
+	0x52, 0x49, 0x54, 0x48, 0x4d, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x00, 0x12, 0x1d, 0x0a, 0x19,
+	0x4b, 0x44, 0x46, 0x5f, 0x41, 0x4c, 0x47, 0x4f, 0x52, 0x49, 0x54, 0x48, 0x4d, 0x5f, 0x48, 0x4b,
+	0x44, 0x46, 0x5f, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x01, 0x12, 0x1f, 0x0a, 0x1b, 0x4b,
+	0x44, 0x46, 0x5f, 0x41, 0x4c, 0x47, 0x4f, 0x52, 0x49, 0x54, 0x48, 0x4d, 0x5f, 0x50, 0x42, 0x4b,
+	0x44, 0x46, 0x32, 0x5f, 0x53, 0x48, 0x41, 0x32, 0x35, 0x36, 0x10, 0x02, 0x2a, 0xd7, 0x01, 0x0a,
+	0x0a, 0x43, 0x61, 0x70, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x16, 0x43,
+	0x41, 0x50, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43,
+	0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x43, 0x41, 0x50, 0x41, 0x42,
+	0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x57, 0x49, 0x4e, 0x44, 0x4f, 0x57, 0x49, 0x4e, 0x47, 0x10,
+	0x01, 0x12, 0x18, 0x0a, 0x14, 0x43, 0x41, 0x50, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f,
+	0x43, 0x48, 0x45, 0x43, 0x4b, 0x53, 0x55, 0x4d, 0x53, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x43,
+	0x41, 0x50, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x52, 0x45, 0x53, 0x55, 0x4d, 0x45,
+	0x10, 0x03, 0x12, 0x1a, 0x0a, 0x16, 0x43, 0x41, 0x50, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59,
+	0x5f, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x04, 0x12, 0x14,
+	0x0a, 0x10, 0x43, 0x41, 0x50, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x49, 0x54, 0x45,
+	0x4d, 0x53, 0x10, 0x05, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x41, 0x50, 0x41, 0x42, 0x49, 0x4c, 0x49,
+	0x54, 0x59, 0x5f, 0x45, 0x4e, 0x43, 0x52, 0x59, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x06, 0x12,
+	0x15, 0x0a, 0x11, 0x43, 0x41, 0x50, 0x41, 0x42, 0x49, 0x4c, 0x49, 0x54, 0x59, 0x5f, 0x45, 0x58,
+	0x50, 0x49, 0x52, 0x59, 0x10, 0x07, 0x2a, 0xc4, 0x05, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72,
+	0x43, 0x6f, 0x64, 0x65, 0x12, 0x1a, 0x0a, 0x16, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f,
+	0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x17, 0x0a, 0x13, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49,
+	0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x21, 0x0a, 0x1d, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x45, 0x58, 0x50, 0x45, 0x43, 0x54,
+	0x45, 0x44, 0x5f, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x10, 0x02, 0x12, 0x1b, 0x0a, 0x17,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c,
+	0x49, 0x44, 0x5f, 0x43, 0x4c, 0x49, 0x50, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x42, 0x55, 0x53, 0x59, 0x10, 0x04, 0x12, 0x19,
+	0x0a, 0x15, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x44, 0x49, 0x53,
+	0x4f, 0x52, 0x44, 0x45, 0x52, 0x45, 0x44, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x41, 0x52, 0x47,
+	0x45, 0x10, 0x06, 0x12, 0x1b, 0x0a, 0x17, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44,
+	0x45, 0x5f, 0x55, 0x4e, 0x41, 0x55, 0x54, 0x48, 0x4f, 0x52, 0x49, 0x5a, 0x45, 0x44, 0x10, 0x07,
+	0x12, 0x1b, 0x0a, 0x17, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x52,
+	0x41, 0x54, 0x45, 0x5f, 0x4c, 0x49, 0x4d, 0x49, 0x54, 0x45, 0x44, 0x10, 0x08, 0x12, 0x20, 0x0a,
+	0x1c, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x50, 0x52, 0x4f, 0x54,
+	0x4f, 0x43, 0x4f, 0x4c, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x09, 0x12,
+	0x20, 0x0a, 0x1c, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x43, 0x48,
+	0x45, 0x43, 0x4b, 0x53, 0x55, 0x4d, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10,
+	0x0a, 0x12, 0x1c, 0x0a, 0x18, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f,
+	0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x0b, 0x12,
+	0x1b, 0x0a, 0x17, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x46, 0x49,
+	0x4c, 0x45, 0x5f, 0x43, 0x48, 0x41, 0x4e, 0x47, 0x45, 0x44, 0x10, 0x0c, 0x12, 0x1d, 0x0a, 0x19,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x50, 0x4c, 0x4f, 0x41,
+	0x44, 0x5f, 0x41, 0x42, 0x4f, 0x52, 0x54, 0x45, 0x44, 0x10, 0x0d, 0x12, 0x1c, 0x0a, 0x18, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49,
+	0x44, 0x5f, 0x43, 0x48, 0x55, 0x4e, 0x4b, 0x10, 0x0e, 0x12, 0x1e, 0x0a, 0x1a, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f,
+	0x56, 0x45, 0x52, 0x53, 0x49, 0x4f, 0x4e, 0x10, 0x0f, 0x12, 0x1b, 0x0a, 0x17, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f,
+	0x49, 0x54, 0x45, 0x4d, 0x10, 0x10, 0x12, 0x1f, 0x0a, 0x1b, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f,
+	0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4d, 0x41, 0x4e,
+	0x49, 0x46, 0x45, 0x53, 0x54, 0x10, 0x11, 0x12, 0x1b, 0x0a, 0x17, 0x45, 0x52, 0x52, 0x4f, 0x52,
+	0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x50, 0x41,
+	0x54, 0x48, 0x10, 0x12, 0x12, 0x1f, 0x0a, 0x1b, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f,
+	0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x45, 0x4e, 0x56, 0x45, 0x4c,
+	0x4f, 0x50, 0x45, 0x10, 0x13, 0x12, 0x22, 0x0a, 0x1e, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43,
+	0x4f, 0x44, 0x45, 0x5f, 0x45, 0x4e, 0x43, 0x52, 0x59, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52,
+	0x45, 0x51, 0x55, 0x49, 0x52, 0x45, 0x44, 0x10, 0x14, 0x12, 0x21, 0x0a, 0x1d, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x42, 0x55, 0x52, 0x4e, 0x5f, 0x41, 0x46, 0x54,
+	0x45, 0x52, 0x5f, 0x52, 0x45, 0x41, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x15, 0x12, 0x1f, 0x0a, 0x1b,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c,
+	0x49, 0x44, 0x5f, 0x4c, 0x49, 0x46, 0x45, 0x54, 0x49, 0x4d, 0x45, 0x10, 0x16, 0x42, 0x09, 0x5a,
+	0x07, 0x70, 0x62, 0x2f, 0x63, 0x6c, 0x69, 0x70, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 })
 
 var (
@@ -498,28 +2708,89 @@ func file_clip_proto_rawDescGZIP() []byte {
 	return file_clip_proto_rawDescData
 }
 
-var file_clip_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_clip_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_clip_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_clip_proto_goTypes = []any{
-	(*Message)(nil),    // 0: clip.Message
-	(*Text)(nil),       // 1: clip.Text
-	(*FileHeader)(nil), // 2: clip.FileHeader
-	(*Chunk)(nil),      // 3: clip.Chunk
-	(*NextChunk)(nil),  // 4: clip.NextChunk
-	(*Ack)(nil),        // 5: clip.Ack
-	(*Error)(nil),      // 6: clip.Error
+	(Cipher)(0),            // 0: clip.Cipher
+	(KdfAlgorithm)(0),      // 1: clip.KdfAlgorithm
+	(Capability)(0),        // 2: clip.Capability
+	(ErrorCode)(0),         // 3: clip.ErrorCode
+	(*Message)(nil),        // 4: clip.Message
+	(*Text)(nil),           // 5: clip.Text
+	(*FileHeader)(nil),     // 6: clip.FileHeader
+	(*Envelope)(nil),       // 7: clip.Envelope
+	(*Kdf)(nil),            // 8: clip.Kdf
+	(*Entry)(nil),          // 9: clip.Entry
+	(*Manifest)(nil),       // 10: clip.Manifest
+	(*Chunk)(nil),          // 11: clip.Chunk
+	(*NextChunk)(nil),      // 12: clip.NextChunk
+	(*Fetch)(nil),          // 13: clip.Fetch
+	(*Ack)(nil),            // 14: clip.Ack
+	(*Cancel)(nil),         // 15: clip.Cancel
+	(*ListVersions)(nil),   // 16: clip.ListVersions
+	(*Version)(nil),        // 17: clip.Version
+	(*Versions)(nil),       // 18: clip.Versions
+	(*RestoreVersion)(nil), // 19: clip.RestoreVersion
+	(*Item)(nil),           // 20: clip.Item
+	(*Items)(nil),          // 21: clip.Items
+	(*AddItem)(nil),        // 22: clip.AddItem
+	(*RemoveItem)(nil),     // 23: clip.RemoveItem
+	(*MoveItem)(nil),       // 24: clip.MoveItem
+	(*Event)(nil),          // 25: clip.Event
+	(*Hello)(nil),          // 26: clip.Hello
+	(*Welcome)(nil),        // 27: clip.Welcome
+	(*Error)(nil),          // 28: clip.Error
+	(*Busy)(nil),           // 29: clip.Busy
+	(*Consumed)(nil),       // 30: clip.Consumed
+	(*Expiry)(nil),         // 31: clip.Expiry
 }
 var file_clip_proto_depIdxs = []int32{
-	1, // 0: clip.Message.text:type_name -> clip.Text
-	2, // 1: clip.Message.hdr:type_name -> clip.FileHeader
-	3, // 2: clip.Message.chunk:type_name -> clip.Chunk
-	4, // 3: clip.Message.nextChunk:type_name -> clip.NextChunk
-	5, // 4: clip.Message.ack:type_name -> clip.Ack
-	6, // 5: clip.Message.err:type_name -> clip.Error
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	5,  // 0: clip.Message.text:type_name -> clip.Text
+	6,  // 1: clip.Message.hdr:type_name -> clip.FileHeader
+	11, // 2: clip.Message.chunk:type_name -> clip.Chunk
+	12, // 3: clip.Message.nextChunk:type_name -> clip.NextChunk
+	14, // 4: clip.Message.ack:type_name -> clip.Ack
+	28, // 5: clip.Message.err:type_name -> clip.Error
+	13, // 6: clip.Message.fetch:type_name -> clip.Fetch
+	26, // 7: clip.Message.hello:type_name -> clip.Hello
+	27, // 8: clip.Message.welcome:type_name -> clip.Welcome
+	15, // 9: clip.Message.cancel:type_name -> clip.Cancel
+	16, // 10: clip.Message.listVersions:type_name -> clip.ListVersions
+	18, // 11: clip.Message.versions:type_name -> clip.Versions
+	19, // 12: clip.Message.restoreVersion:type_name -> clip.RestoreVersion
+	22, // 13: clip.Message.addItem:type_name -> clip.AddItem
+	23, // 14: clip.Message.removeItem:type_name -> clip.RemoveItem
+	24, // 15: clip.Message.moveItem:type_name -> clip.MoveItem
+	21, // 16: clip.Message.items:type_name -> clip.Items
+	25, // 17: clip.Message.event:type_name -> clip.Event
+	30, // 18: clip.Message.consumed:type_name -> clip.Consumed
+	31, // 19: clip.Message.expiry:type_name -> clip.Expiry
+	7,  // 20: clip.Text.envelope:type_name -> clip.Envelope
+	10, // 21: clip.FileHeader.manifest:type_name -> clip.Manifest
+	7,  // 22: clip.FileHeader.envelope:type_name -> clip.Envelope
+	0,  // 23: clip.Envelope.cipher:type_name -> clip.Cipher
+	8,  // 24: clip.Envelope.kdf:type_name -> clip.Kdf
+	1,  // 25: clip.Kdf.algorithm:type_name -> clip.KdfAlgorithm
+	9,  // 26: clip.Manifest.entries:type_name -> clip.Entry
+	5,  // 27: clip.Version.text:type_name -> clip.Text
+	6,  // 28: clip.Version.hdr:type_name -> clip.FileHeader
+	20, // 29: clip.Version.items:type_name -> clip.Item
+	17, // 30: clip.Versions.versions:type_name -> clip.Version
+	5,  // 31: clip.Item.text:type_name -> clip.Text
+	6,  // 32: clip.Item.hdr:type_name -> clip.FileHeader
+	20, // 33: clip.Items.items:type_name -> clip.Item
+	5,  // 34: clip.AddItem.text:type_name -> clip.Text
+	6,  // 35: clip.AddItem.hdr:type_name -> clip.FileHeader
+	20, // 36: clip.Event.items:type_name -> clip.Item
+	2,  // 37: clip.Hello.capabilities:type_name -> clip.Capability
+	2,  // 38: clip.Welcome.capabilities:type_name -> clip.Capability
+	3,  // 39: clip.Error.code:type_name -> clip.ErrorCode
+	29, // 40: clip.Error.busy:type_name -> clip.Busy
+	41, // [41:41] is the sub-list for method output_type
+	41, // [41:41] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_clip_proto_init() }
@@ -534,19 +2805,49 @@ func file_clip_proto_init() {
 		(*Message_NextChunk)(nil),
 		(*Message_Ack)(nil),
 		(*Message_Err)(nil),
+		(*Message_Fetch)(nil),
+		(*Message_Hello)(nil),
+		(*Message_Welcome)(nil),
+		(*Message_Cancel)(nil),
+		(*Message_ListVersions)(nil),
+		(*Message_Versions)(nil),
+		(*Message_RestoreVersion)(nil),
+		(*Message_AddItem)(nil),
+		(*Message_RemoveItem)(nil),
+		(*Message_MoveItem)(nil),
+		(*Message_Items)(nil),
+		(*Message_Event)(nil),
+		(*Message_Consumed)(nil),
+		(*Message_Expiry)(nil),
+	}
+	file_clip_proto_msgTypes[13].OneofWrappers = []any{
+		(*Version_Text)(nil),
+		(*Version_Hdr)(nil),
+	}
+	file_clip_proto_msgTypes[16].OneofWrappers = []any{
+		(*Item_Text)(nil),
+		(*Item_Hdr)(nil),
+	}
+	file_clip_proto_msgTypes[18].OneofWrappers = []any{
+		(*AddItem_Text)(nil),
+		(*AddItem_Hdr)(nil),
+	}
+	file_clip_proto_msgTypes[24].OneofWrappers = []any{
+		(*Error_Busy)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_clip_proto_rawDesc), len(file_clip_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      4,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_clip_proto_goTypes,
 		DependencyIndexes: file_clip_proto_depIdxs,
+		EnumInfos:         file_clip_proto_enumTypes,
 		MessageInfos:      file_clip_proto_msgTypes,
 	}.Build()
 	File_clip_proto = out.File